@@ -2,112 +2,76 @@ package async
 
 import (
 	"context"
-	"sync"
+	"errors"
 )
 
 // Task is a function that can be run concurrently.
 type Task func() error
 
+// TaskContext is a function that can be run concurrently and is given a context that is cancelled when its work should stop.
+type TaskContext func(ctx context.Context) error
+
+// taskNameKey is the context key for the name given to tasks with WithTaskName.
+type taskNameKey struct{}
+
+// WithTaskName returns a copy of ctx that names the tasks run with it, so that errors, tracers and logs identify the task as well as the runner or pool executing it.
+func WithTaskName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, taskNameKey{}, name)
+}
+
+// TaskName returns the name given to tasks run with ctx, or an empty string if they were not named.
+func TaskName(ctx context.Context) string {
+	name, _ := ctx.Value(taskNameKey{}).(string)
+	return name
+}
+
+// withContext adapts a task to accept a context that it ignores.
+func withContext(task Task) TaskContext {
+	return func(context.Context) error {
+		return task()
+	}
+}
+
 // Run will execute the given tasks concurrently and return any errors.
 func Run(tasks ...Task) <-chan error {
-	errc := make(chan error)
-
-	// run tasks
-	var wg sync.WaitGroup
-	for _, v := range tasks {
-		wg.Add(1)
-		go func(task Task) {
-			defer wg.Done()
-			err := task()
-			if err != nil {
-				errc <- err
-			}
-		}(v)
+	wrapped := make([]TaskContext, len(tasks))
+	for i, v := range tasks {
+		wrapped[i] = withContext(v)
 	}
 
-	// make sure to close error channel
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
+	return RunContext(context.Background(), wrapped...)
+}
 
-	return errc
+// RunContext will execute the given tasks concurrently and return any errors. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress.
+func RunContext(ctx context.Context, tasks ...TaskContext) <-chan error {
+	return defaultRunner.Run(ctx, tasks...)
 }
 
 // RunForever will execute the given task repeatedly on a set number of goroutines and return any errors. Context can be used to cancel execution of additional tasks.
 func RunForever(ctx context.Context, concurrent int, task Task) <-chan error {
-	errc := make(chan error)
-
-	// run tasks
-	var wg sync.WaitGroup
-	for c := 0; c < concurrent; c++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for {
-				err := task()
-				if err != nil {
-					errc <- err
-				}
-
-				select {
-				case <-ctx.Done():
-					errc <- ctx.Err()
-					return
-				default:
-				}
-			}
-		}()
-	}
-
-	// make sure to close error channel
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
+	return RunForeverContext(ctx, concurrent, withContext(task))
+}
 
-	return errc
+// RunForeverContext will execute the given task repeatedly on a set number of goroutines and return any errors. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress as well as stop additional tasks from being started.
+func RunForeverContext(ctx context.Context, concurrent int, task TaskContext) <-chan error {
+	return defaultRunner.RunForever(ctx, concurrent, task)
 }
 
 // RunLimited will execute the given task a set number of times on a set number of goroutines and return any errors. Total times the task will be executed is equal to concurrent multiplied by count. Context can be used to cancel execution of additional tasks.
 func RunLimited(ctx context.Context, concurrent int, count int, task Task) <-chan error {
-	errc := make(chan error)
-
-	// run tasks
-	var wg sync.WaitGroup
-	for c := 0; c < concurrent; c++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for i := 0; i < count; i++ {
-				err := task()
-				if err != nil {
-					errc <- err
-				}
-
-				select {
-				case <-ctx.Done():
-					errc <- ctx.Err()
-					return
-				default:
-				}
-			}
-		}()
-	}
-
-	// make sure to close error channel
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
+	return RunLimitedContext(ctx, concurrent, count, withContext(task))
+}
 
-	return errc
+// RunLimitedContext will execute the given task a set number of times on a set number of goroutines and return any errors. Total times the task will be executed is equal to concurrent multiplied by count. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress as well as stop additional tasks from being started.
+func RunLimitedContext(ctx context.Context, concurrent int, count int, task TaskContext) <-chan error {
+	return defaultRunner.RunLimited(ctx, concurrent, count, task)
 }
 
-// Wait until channel is closed or error is received.
+// Wait until channel is closed or error is received. If an error is received before the channel is closed, the channel is discarded.
 func Wait(errc <-chan error) error {
 	for err := range errc {
 		if err != nil {
+			Discard(errc)
 			return err
 		}
 	}
@@ -115,6 +79,25 @@ func Wait(errc <-chan error) error {
 	return nil
 }
 
+// Discard stops delivery of errors on a channel returned by a runner and cancels the context given to its tasks, so that no goroutines are left blocked once the channel is no longer being read. Discarding a channel that was not returned by a runner or is already closed has no effect.
+func Discard(errc <-chan error) {
+	if v, ok := channels.Load(errc); ok {
+		v.(*errChan).discard()
+	}
+}
+
+// WaitAll waits until the channel is closed and returns every error received joined together with errors.Join, or nil if no errors were received. Runners and pools created with WithTaskErrors return a *TaskError identifying the task that failed, and a recovered panic is always a *PanicError.
+func WaitAll(errc <-chan error) error {
+	var errs []error
+	for err := range errc {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // HandleError sets a handler function to be called anytime an error is received on the given channel.
 func HandleError(errc <-chan error, handler func(error)) {
 	go func() {
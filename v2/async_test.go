@@ -3,6 +3,8 @@ package async
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -75,6 +77,49 @@ func Test_Run_Error(t *testing.T) {
 	assert.True(t, task3Completed)
 }
 
+func Test_RunContext_Success(t *testing.T) {
+	// arrange
+	var count int32
+	task := func(ctx context.Context) error {
+		assert.NotNil(t, ctx)
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+
+	// act
+	errc := RunContext(context.Background(), task, task, task)
+	err := Wait(errc)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), count)
+}
+
+func Test_RunContext_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan bool)
+	task := func(ctx context.Context) error {
+		started <- true
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second * 5):
+			return nil
+		}
+	}
+
+	// act
+	errc := RunContext(ctx, task)
+	<-started
+	cancel()
+	err := Wait(errc)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
 func Test_RunLimited_Success(t *testing.T) {
 	// arrange
 	ctx := context.Background()
@@ -144,6 +189,25 @@ func Test_RunLimited_Error(t *testing.T) {
 	assert.True(t, count < 12)
 }
 
+func Test_RunLimitedContext_Success(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+
+	var count int32
+	task := func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+
+	// act
+	errc := RunLimitedContext(ctx, 3, 4, task)
+	err := Wait(errc)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(12), count)
+}
+
 func Test_RunForever_Cancel(t *testing.T) {
 	// arrange
 	ctx, cancel := context.WithCancel(context.Background())
@@ -226,6 +290,203 @@ func Test_RunForever_Error(t *testing.T) {
 	assert.True(t, count >= 10)
 }
 
+func Test_RunForeverContext_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var count int32
+	task := func(ctx context.Context) error {
+		if atomic.AddInt32(&count, 1) == 10 {
+			cancel()
+		}
+
+		// in-flight tasks are interrupted by the cancellation
+		<-ctx.Done()
+		return nil
+	}
+
+	// act
+	errc := RunForeverContext(ctx, 10, task)
+	err := Wait(errc)
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&count))
+}
+
+func Test_WithTaskName_Success(t *testing.T) {
+	// arrange
+	ctx := WithTaskName(context.Background(), "fetch")
+
+	runner := NewRunner(WithTaskErrors(true))
+
+	// act
+	errc := runner.Run(ctx, func(ctx context.Context) error {
+		return fmt.Errorf("task error in %s", TaskName(ctx))
+	})
+	err := Wait(errc)
+
+	// assert
+	var taskErr *TaskError
+	assert.True(t, errors.As(err, &taskErr))
+	assert.Equal(t, "fetch", taskErr.Task.TaskName)
+	assert.Equal(t, "task 0 (fetch): task error in fetch", err.Error())
+}
+
+func Test_TaskName_Empty(t *testing.T) {
+	// assert
+	assert.Equal(t, "", TaskName(context.Background()))
+}
+
+func Test_TaskInfo_String(t *testing.T) {
+	// assert
+	assert.Equal(t, "task 1", TaskInfo{Index: 1}.String())
+	assert.Equal(t, "pool task 1", TaskInfo{Name: "pool", Index: 1}.String())
+	assert.Equal(t, "task 1 (fetch)", TaskInfo{TaskName: "fetch", Index: 1}.String())
+	assert.Equal(t, "pool task 1 (fetch)", TaskInfo{Name: "pool", TaskName: "fetch", Index: 1}.String())
+}
+
+func Test_WaitAll_Success(t *testing.T) {
+	// arrange
+	task := func() error {
+		return nil
+	}
+
+	// act
+	errc := Run(task, task, task)
+	err := WaitAll(errc)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func Test_WaitAll_Error(t *testing.T) {
+	// arrange
+	errTask1 := errors.New("task1 error")
+	task1 := func() error {
+		return errTask1
+	}
+
+	task2 := func() error {
+		return nil
+	}
+
+	task3 := func() error {
+		time.Sleep(time.Millisecond * 100)
+		return errors.New("task3 error")
+	}
+
+	runner := NewRunner(WithTaskErrors(true))
+
+	// act
+	errc := runner.Run(context.Background(), withContext(task1), withContext(task2), withContext(task3))
+	err := WaitAll(errc)
+
+	// assert
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, errTask1))
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	assert.Len(t, errs, 2)
+
+	var taskErr *TaskError
+	assert.True(t, errors.As(errs[0], &taskErr))
+	assert.Equal(t, 0, taskErr.Task.Index)
+	assert.True(t, errors.As(errs[1], &taskErr))
+	assert.Equal(t, 2, taskErr.Task.Index)
+	assert.EqualError(t, taskErr, "task 2: task3 error")
+}
+
+func Test_WaitAll_Unwrapped(t *testing.T) {
+	// arrange
+	errTask := errors.New("task error")
+	task := func() error {
+		return errTask
+	}
+
+	// act
+	err := Wait(Run(task))
+
+	// assert
+	assert.Equal(t, errTask, err)
+	assert.EqualError(t, err, "task error")
+}
+
+func Test_Wait_Run_NoGoroutineLeak(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	task := func() error {
+		return errors.New("task error")
+	}
+
+	// act
+	errc := Run(task, task, task, task, task)
+	err := Wait(errc)
+
+	// assert
+	assert.Error(t, err)
+	assertGoroutines(t, before)
+}
+
+func Test_Wait_RunForever_NoGoroutineLeak(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	task := func() error {
+		return errors.New("task error")
+	}
+
+	// act
+	errc := RunForever(context.Background(), 5, task)
+	err := Wait(errc)
+
+	// assert
+	assert.Error(t, err)
+	assertGoroutines(t, before)
+}
+
+func Test_Wait_RunLimited_NoGoroutineLeak(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	task := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// act
+	errc := RunLimitedContext(ctx, 5, 10, task)
+	cancel()
+	err := Wait(errc)
+
+	// assert
+	assert.Error(t, err)
+	assertGoroutines(t, before)
+}
+
+func Test_Discard_Success(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	task := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	errc := RunForeverContext(context.Background(), 5, task)
+
+	// act
+	Discard(errc)
+
+	// assert
+	assertGoroutines(t, before)
+	_, ok := <-errc
+	assert.False(t, ok)
+}
+
 func Test_HandleError_Success(t *testing.T) {
 	// arrange
 	var wg sync.WaitGroup
@@ -259,6 +520,24 @@ func Test_HandleError_Success(t *testing.T) {
 	assert.Equal(t, 3, count)
 }
 
+// assertGoroutines fails the test if the number of goroutines does not return to at most n.
+func assertGoroutines(t *testing.T, n int) {
+	deadline := time.Now().Add(time.Second * 2)
+	for runtime.NumGoroutine() > n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), n)
+}
+
+// assertEventually fails the test if condition does not become true. It polls instead of using assert.Eventually, which can panic in the pinned version of testify.
+func assertEventually(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(time.Second * 2)
+	for !condition() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, condition())
+}
+
 func Benchmark_Run(b *testing.B) {
 	task := func() error {
 		return nil
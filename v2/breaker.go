@@ -0,0 +1,260 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of executing a task while a breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// errAborted is recorded with a breaker when an admitted task is never executed.
+var errAborted = errors.New("task aborted")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed allows every task to be executed.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects every task with ErrCircuitOpen until the cooldown has passed.
+	BreakerOpen
+
+	// BreakerHalfOpen allows a limited number of trial tasks to decide whether to close or open again.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerSettings configures when a breaker trips and recovers.
+type BreakerSettings struct {
+	// ConsecutiveFailures trips the breaker after this many failures in a row. Zero disables this condition.
+	ConsecutiveFailures int
+
+	// FailureRatio trips the breaker once the ratio of failures to tasks reaches this value. Zero disables this condition.
+	FailureRatio float64
+
+	// MinRequests is the number of tasks that must complete before FailureRatio is considered. Defaults to 10, so a single failure doesn't trip the breaker.
+	MinRequests int
+
+	// Interval is how often the counts used by FailureRatio are reset while the breaker is closed. Zero never resets them.
+	Interval time.Duration
+
+	// Cooldown is how long the breaker stays open before allowing trial tasks.
+	Cooldown time.Duration
+
+	// HalfOpenRequests is the number of trial tasks allowed while half-open, all of which must succeed to close the breaker. Defaults to 1.
+	HalfOpenRequests int
+
+	// IsFailure reports whether an error counts as a failure. Nil counts every error.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called whenever the breaker changes state.
+	OnStateChange func(from BreakerState, to BreakerState)
+}
+
+// Breaker stops executing tasks while a dependency is failing and lets them through again once it recovers.
+type Breaker struct {
+	settings BreakerSettings
+
+	mu          sync.Mutex
+	state       BreakerState
+	generation  uint64
+	changed     time.Time
+	requests    int
+	failures    int
+	consecutive int
+	trials      int
+	successes   int
+}
+
+// defaultMinRequests is the number of tasks that must complete before FailureRatio is considered unless set in BreakerSettings.
+const defaultMinRequests = 10
+
+// NewBreaker creates a new breaker that starts closed.
+func NewBreaker(settings BreakerSettings) *Breaker {
+	if settings.HalfOpenRequests <= 0 {
+		settings.HalfOpenRequests = 1
+	}
+	if settings.MinRequests <= 0 {
+		settings.MinRequests = defaultMinRequests
+	}
+
+	return &Breaker{
+		settings: settings,
+		changed:  time.Now(),
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	state, notify := b.refresh(time.Now())
+	b.mu.Unlock()
+
+	notify()
+	return state
+}
+
+// Wrap returns a task that fails with ErrCircuitOpen without executing the given task while the breaker is open, and otherwise records the result of the task with the breaker. The breaker is only checked once the wrapped task runs, so a wrapped task given to a TaskPool still waits for and takes capacity; use WithBreaker on the pool to reject tasks before they take a slot.
+func (b *Breaker) Wrap(task TaskContext) TaskContext {
+	return func(ctx context.Context) error {
+		done, err := b.allow()
+		if err != nil {
+			return err
+		}
+
+		err = task(ctx)
+		done(err)
+		return err
+	}
+}
+
+// allow reserves permission to execute a task. The returned function records the result of the task, or releases the reservation if given errAborted.
+func (b *Breaker) allow() (func(err error), error) {
+	b.mu.Lock()
+	state, notify := b.refresh(time.Now())
+	defer notify()
+	defer b.mu.Unlock()
+
+	switch state {
+	case BreakerOpen:
+		return nil, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.trials >= b.settings.HalfOpenRequests {
+			return nil, ErrCircuitOpen
+		}
+		b.trials++
+	}
+
+	generation := b.generation
+	return func(err error) {
+		b.record(generation, err)
+	}, nil
+}
+
+// record updates the counts with the result of a task admitted during the given generation.
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	now := time.Now()
+	_, notify := b.refresh(now)
+	defer func() { notify() }()
+	defer b.mu.Unlock()
+
+	// ignore results from before the last state change
+	if generation != b.generation {
+		return
+	}
+
+	if err == errAborted {
+		if b.state == BreakerHalfOpen {
+			b.trials--
+		}
+		return
+	}
+
+	failed := err != nil && (b.settings.IsFailure == nil || b.settings.IsFailure(err))
+
+	switch b.state {
+	case BreakerClosed:
+		b.requests++
+		if !failed {
+			b.consecutive = 0
+			return
+		}
+
+		b.failures++
+		b.consecutive++
+		if b.tripped() {
+			notify = chain(notify, b.setState(BreakerOpen, now))
+		}
+	case BreakerHalfOpen:
+		if failed {
+			notify = chain(notify, b.setState(BreakerOpen, now))
+			return
+		}
+
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			notify = chain(notify, b.setState(BreakerClosed, now))
+		}
+	}
+}
+
+// tripped reports whether the counts meet a condition for opening the breaker.
+func (b *Breaker) tripped() bool {
+	if b.settings.ConsecutiveFailures > 0 && b.consecutive >= b.settings.ConsecutiveFailures {
+		return true
+	}
+
+	return b.settings.FailureRatio > 0 &&
+		b.requests >= b.settings.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.settings.FailureRatio
+}
+
+// refresh applies transitions that happen with the passing of time and returns the current state.
+func (b *Breaker) refresh(now time.Time) (BreakerState, func()) {
+	notify := func() {}
+
+	switch b.state {
+	case BreakerClosed:
+		if b.settings.Interval > 0 && now.Sub(b.changed) >= b.settings.Interval {
+			b.reset(now)
+		}
+	case BreakerOpen:
+		if now.Sub(b.changed) >= b.settings.Cooldown {
+			notify = b.setState(BreakerHalfOpen, now)
+		}
+	}
+
+	return b.state, notify
+}
+
+// setState changes the state of the breaker and returns a function that calls OnStateChange.
+func (b *Breaker) setState(state BreakerState, now time.Time) func() {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.reset(now)
+
+	if b.settings.OnStateChange == nil {
+		return func() {}
+	}
+
+	return func() {
+		b.settings.OnStateChange(from, state)
+	}
+}
+
+// reset clears all counts.
+func (b *Breaker) reset(now time.Time) {
+	b.changed = now
+	b.requests = 0
+	b.failures = 0
+	b.consecutive = 0
+	b.trials = 0
+	b.successes = 0
+}
+
+// chain returns a function that calls both functions in order.
+func chain(first func(), second func()) func() {
+	return func() {
+		first()
+		second()
+	}
+}
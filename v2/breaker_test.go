@@ -0,0 +1,195 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Breaker_ConsecutiveFailures(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		ConsecutiveFailures: 3,
+		Cooldown:            time.Minute,
+	})
+
+	count := 0
+	task := breaker.Wrap(func(ctx context.Context) error {
+		count++
+		return errors.New("task error")
+	})
+
+	// act
+	for i := 0; i < 5; i++ {
+		task(context.Background())
+	}
+	err := task(context.Background())
+
+	// assert
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func Test_Breaker_FailureRatio(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Cooldown:     time.Minute,
+	})
+
+	fail := breaker.Wrap(func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+	succeed := breaker.Wrap(func(ctx context.Context) error {
+		return nil
+	})
+
+	// act
+	succeed(context.Background())
+	fail(context.Background())
+	succeed(context.Background())
+	assert.Equal(t, BreakerClosed, breaker.State())
+	fail(context.Background())
+
+	// assert
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func Test_Breaker_HalfOpen_Recovers(t *testing.T) {
+	// arrange
+	var mu sync.Mutex
+	var changes []BreakerState
+	breaker := NewBreaker(BreakerSettings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Millisecond * 50,
+		OnStateChange: func(from BreakerState, to BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, to)
+		},
+	})
+
+	fail := true
+	task := breaker.Wrap(func(ctx context.Context) error {
+		if fail {
+			return errors.New("task error")
+		}
+		return nil
+	})
+
+	// act
+	task(context.Background())
+	assert.Equal(t, ErrCircuitOpen, task(context.Background()))
+
+	time.Sleep(time.Millisecond * 60)
+	fail = false
+	err := task(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerClosed}, changes)
+}
+
+func Test_Breaker_HalfOpen_Fails(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Millisecond * 50,
+	})
+
+	task := breaker.Wrap(func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+
+	// act
+	task(context.Background())
+	time.Sleep(time.Millisecond * 60)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	task(context.Background())
+
+	// assert
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func Test_TaskPool_WithBreaker_DoesNotAcquire(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Minute,
+	})
+	pool := NewTaskPool(1, WithBreaker(breaker))
+
+	err := <-pool.Run(context.Background(), func() error {
+		return errors.New("task error")
+	})
+	assert.Error(t, err)
+
+	// hold the only slot so that an admitted task would block
+	assert.NoError(t, pool.sem.Acquire(context.Background(), 1))
+	defer pool.sem.Release(1)
+
+	// act
+	errc := pool.Run(context.Background(), func() error {
+		return nil
+	})
+
+	// assert
+	assert.Equal(t, ErrCircuitOpen, <-errc)
+}
+
+func Test_TaskPool_WithBreaker_Closed(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Millisecond * 50,
+	})
+	pool := NewTaskPool(1, WithBreaker(breaker))
+
+	err := <-pool.Run(context.Background(), func() error {
+		return errors.New("task error")
+	})
+	assert.Error(t, err)
+	pool.Close()
+
+	// act
+	errOpen := <-pool.Run(context.Background(), func() error { return nil })
+	time.Sleep(time.Millisecond * 60)
+	errHalfOpen := <-pool.Run(context.Background(), func() error { return nil })
+
+	// assert
+	assert.Equal(t, ErrPoolClosed, errOpen)
+	assert.Equal(t, ErrPoolClosed, errHalfOpen)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.NoError(t, breaker.Wrap(func(ctx context.Context) error { return nil })(context.Background()))
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func Test_Breaker_FailureRatio_DefaultMinRequests(t *testing.T) {
+	// arrange
+	breaker := NewBreaker(BreakerSettings{
+		FailureRatio: 0.5,
+		Cooldown:     time.Minute,
+	})
+
+	fail := breaker.Wrap(func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+
+	// act
+	fail(context.Background())
+	state := breaker.State()
+	for i := 1; i < 10; i++ {
+		fail(context.Background())
+	}
+
+	// assert
+	assert.Equal(t, BreakerClosed, state)
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
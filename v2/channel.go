@@ -0,0 +1,106 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge combines error channels into a single channel that is closed once every input channel is closed. Like the channels returned by runners, the merged channel can be discarded, and discarding it or cancelling ctx discards every input channel, so that runners feeding it are cancelled and no goroutines are left blocked. This makes it safe to pass the merged channel to Wait, WaitAll or HandleError.
+func Merge(ctx context.Context, chans ...<-chan error) <-chan error {
+	ctx, cancel := context.WithCancel(ctx)
+	errc := newErrChan(cancel)
+
+	var wg sync.WaitGroup
+	for _, c := range chans {
+		wg.Add(1)
+		go func(c <-chan error) {
+			defer wg.Done()
+			for {
+				select {
+				case err, ok := <-c:
+					if !ok {
+						return
+					}
+					errc.send(err)
+				case <-ctx.Done():
+					Discard(c)
+					return
+				}
+			}
+		}(c)
+	}
+
+	// close channel when all inputs are finished
+	go func() {
+		wg.Wait()
+		errc.close()
+	}()
+
+	return errc.c
+}
+
+// Broadcast sends every item received from in to each of n output channels, which are closed once in is closed or ctx is cancelled. Each item is delivered to all outputs before the next item is read, so the slowest reader sets the pace and every output must be read until it is closed or ctx is cancelled.
+func Broadcast[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		panic("n must be a value of >= 1")
+	}
+
+	outs := make([]<-chan T, n)
+	inboxes := make([]chan T, n)
+	acks := make(chan struct{}, n)
+
+	// start a forwarder for each output so a slow reader doesn't stop the others from receiving an item
+	for i := range inboxes {
+		inbox := make(chan T)
+		out := make(chan T)
+		inboxes[i] = inbox
+		outs[i] = out
+
+		go func() {
+			defer close(out)
+			for item := range inbox {
+				select {
+				case out <- item:
+				case <-ctx.Done():
+				}
+				acks <- struct{}{}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			for _, inbox := range inboxes {
+				close(inbox)
+			}
+		}()
+
+		for {
+			var item T
+			var ok bool
+			select {
+			case item, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			for _, inbox := range inboxes {
+				inbox <- item
+			}
+			for range inboxes {
+				<-acks
+			}
+		}
+	}()
+
+	return outs
+}
+
+// Tee sends every item received from in to both returned channels, which are closed once in is closed or ctx is cancelled. Both channels must be read until they are closed or ctx is cancelled.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	outs := Broadcast(ctx, in, 2)
+	return outs[0], outs[1]
+}
@@ -0,0 +1,191 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Merge_Success(t *testing.T) {
+	// arrange
+	task := func() error {
+		return nil
+	}
+	errc1 := Run(task, task)
+	errc2 := RunLimited(context.Background(), 2, 5, task)
+
+	// act
+	err := Wait(Merge(context.Background(), errc1, errc2))
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func Test_Merge_Errors(t *testing.T) {
+	// arrange
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	errc1 := Run(func() error { return err1 })
+	errc2 := Run(func() error { return nil }, func() error { return err2 })
+
+	// act
+	err := WaitAll(Merge(context.Background(), errc1, errc2))
+
+	// assert
+	assert.True(t, errors.Is(err, err1))
+	assert.True(t, errors.Is(err, err2))
+}
+
+func Test_Merge_Empty(t *testing.T) {
+	// act
+	errc := Merge(context.Background())
+
+	// assert
+	_, ok := <-errc
+	assert.False(t, ok)
+}
+
+func Test_Merge_HandleError(t *testing.T) {
+	// arrange
+	var mu sync.Mutex
+	var errs []string
+	errc1 := Run(func() error { return errors.New("a") })
+	errc2 := Run(func() error { return errors.New("b") })
+
+	// act
+	merged := Merge(context.Background(), errc1, errc2)
+	HandleError(merged, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err.Error())
+	})
+
+	// assert
+	assertEventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 2
+	})
+	sort.Strings(errs)
+	assert.Equal(t, []string{"a", "b"}, errs)
+}
+
+func Test_Merge_Wait_NoLeak(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+	task := func(ctx context.Context) error {
+		return errors.New("task error")
+	}
+	errc1 := RunForeverContext(context.Background(), 4, task)
+	errc2 := RunForeverContext(context.Background(), 4, task)
+
+	// act
+	err := Wait(Merge(context.Background(), errc1, errc2))
+
+	// assert
+	assert.Error(t, err)
+	assertGoroutines(t, before)
+}
+
+func Test_Merge_Cancel(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := RunForeverContext(context.Background(), 2, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// act
+	merged := Merge(ctx, errc)
+	cancel()
+	for range merged {
+	}
+
+	// assert
+	assertGoroutines(t, before)
+}
+
+func Test_Broadcast_Success(t *testing.T) {
+	// arrange
+	outs := Broadcast(context.Background(), feed(1, 2, 3, 4, 5), 3)
+	results := make([][]int, len(outs))
+
+	// act
+	var wg sync.WaitGroup
+	for i, out := range outs {
+		wg.Add(1)
+		go func(i int, out <-chan int) {
+			defer wg.Done()
+			for item := range out {
+				results[i] = append(results[i], item)
+			}
+		}(i, out)
+	}
+	wg.Wait()
+
+	// assert
+	for _, r := range results {
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, r)
+	}
+}
+
+func Test_Broadcast_Cancel(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	outs := Broadcast(ctx, in, 2)
+	go func() {
+		in <- 1
+	}()
+
+	// act
+	assert.Equal(t, 1, <-outs[0])
+	cancel()
+	for range outs[0] {
+	}
+	for range outs[1] {
+	}
+
+	// assert
+	assertGoroutines(t, before)
+}
+
+func Test_Broadcast_Invalid(t *testing.T) {
+	// assert
+	assert.Panics(t, func() { Broadcast(context.Background(), feed(), 0) })
+}
+
+func Test_Tee_Success(t *testing.T) {
+	// arrange
+	out1, out2 := Tee(context.Background(), feed(1, 2, 3))
+	var results1, results2 []int
+
+	// act
+	for out1 != nil || out2 != nil {
+		select {
+		case item, ok := <-out1:
+			if !ok {
+				out1 = nil
+				continue
+			}
+			results1 = append(results1, item)
+		case item, ok := <-out2:
+			if !ok {
+				out2 = nil
+				continue
+			}
+			results2 = append(results2, item)
+		}
+	}
+
+	// assert
+	assert.Equal(t, []int{1, 2, 3}, results1)
+	assert.Equal(t, []int{1, 2, 3}, results2)
+}
@@ -0,0 +1,74 @@
+package async
+
+import (
+	"fmt"
+)
+
+// TaskInfo identifies a single execution of a task.
+type TaskInfo struct {
+	// Name is the name of the runner or pool executing the task, set with WithName.
+	Name string
+
+	// TaskName is the name of the task itself, set on the context given to the runner or pool with WithTaskName.
+	TaskName string
+
+	// Index is the position of the task given to Run, or the goroutine executing the task for RunForever and RunLimited.
+	Index int
+
+	// Iteration is the number of times the same goroutine previously executed the task.
+	Iteration int
+}
+
+// String returns a description of the task.
+func (i TaskInfo) String() string {
+	s := fmt.Sprintf("task %d", i.Index)
+	if i.Name != "" {
+		s = i.Name + " " + s
+	}
+	if i.TaskName != "" {
+		s += " (" + i.TaskName + ")"
+	}
+	return s
+}
+
+// TaskError is returned when a task fails and identifies which task returned the error.
+type TaskError struct {
+	// Task identifies the task that failed.
+	Task TaskInfo
+
+	// Err is the error returned by the task.
+	Err error
+}
+
+// Error returns a description of the failure.
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Task, e.Err)
+}
+
+// Unwrap returns the error returned by the task.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is returned when a task panics.
+type PanicError struct {
+	// Task identifies the task that panicked.
+	Task TaskInfo
+
+	// Value is the value that was recovered from the panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error returns a description of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v panicked: %v", e.Task, e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
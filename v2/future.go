@@ -0,0 +1,84 @@
+package async
+
+import (
+	"context"
+	"errors"
+)
+
+// Future holds the result of a function that is running concurrently.
+type Future[T any] struct {
+	done   chan struct{}
+	result chan T
+	value  T
+	err    error
+}
+
+// newFuture creates a future that has not yet completed.
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done:   make(chan struct{}),
+		result: make(chan T, 1),
+	}
+}
+
+// Go will execute the given function concurrently and return a future for its result.
+func Go[T any](fn func() (T, error)) *Future[T] {
+	f := newFuture[T]()
+	errc := Run(f.task(fn))
+	go f.complete(errc)
+	return f
+}
+
+// Submit will block until there is available capacity in the pool and then execute the given function, returning a future for its result. Cancelling the context will stop the function from being started.
+func Submit[T any](ctx context.Context, p *TaskPool, fn func() (T, error)) *Future[T] {
+	return SubmitContext(ctx, p, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// SubmitContext will block until there is available capacity in the pool and then execute the given function, returning a future for its result. Cancelling the context will stop the function from being started and interrupt it while it is in progress.
+func SubmitContext[T any](ctx context.Context, p *TaskPool, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := newFuture[T]()
+	errc := p.RunContext(ctx, func(ctx context.Context) error {
+		return f.task(func() (T, error) { return fn(ctx) })()
+	})
+	go f.complete(errc)
+	return f
+}
+
+// Get will block until the result is available or the context is cancelled.
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed once the result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// task adapts fn to a task that hands its value to the future. The value is sent rather than stored so that a task abandoned after a timeout cannot change the future once it has completed.
+func (f *Future[T]) task(fn func() (T, error)) Task {
+	return func() error {
+		value, err := fn()
+		f.result <- value
+		return err
+	}
+}
+
+// complete records the error from errc and the value from the task if it finished, and marks the future as done.
+func (f *Future[T]) complete(errc <-chan error) {
+	f.err = Wait(errc)
+	if !errors.Is(f.err, ErrTaskTimeout) {
+		select {
+		case f.value = <-f.result:
+		default:
+		}
+	}
+	close(f.done)
+}
@@ -0,0 +1,118 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Go_Success(t *testing.T) {
+	// act
+	future := Go(func() (int, error) {
+		return 42, nil
+	})
+	result, err := future.Get(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func Test_Go_Error(t *testing.T) {
+	// act
+	future := Go(func() (string, error) {
+		return "", errors.New("task error")
+	})
+	<-future.Done()
+	result, err := future.Get(context.Background())
+
+	// assert
+	assert.Error(t, err)
+	assert.Equal(t, "", result)
+}
+
+func Test_Future_Get_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	future := Go(func() (int, error) {
+		time.Sleep(time.Millisecond * 500)
+		return 42, nil
+	})
+
+	// act
+	result, err := future.Get(ctx)
+
+	// assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 0, result)
+}
+
+func Test_Submit_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	// act
+	futures := make([]*Future[int], 5)
+	for i := range futures {
+		i := i
+		futures[i] = Submit(context.Background(), pool, func() (int, error) {
+			return i * i, nil
+		})
+	}
+
+	// assert
+	for i, future := range futures {
+		result, err := future.Get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, i*i, result)
+	}
+}
+
+func Test_SubmitContext_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewTaskPool(1)
+
+	started := make(chan bool)
+
+	// act
+	future := SubmitContext(ctx, pool, func(ctx context.Context) (int, error) {
+		started <- true
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	<-started
+	cancel()
+
+	// assert
+	_, err := future.Get(context.Background())
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_SubmitContext_TimeoutAbandon(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1, WithTimeout(time.Millisecond*20, TimeoutAbandon))
+	release := make(chan bool)
+
+	// act
+	future := SubmitContext(context.Background(), pool, func(ctx context.Context) (int, error) {
+		<-release
+		return 42, nil
+	})
+	value, err := future.Get(context.Background())
+
+	close(release)
+	assertEventually(t, func() bool { return pool.Leaked() == 0 })
+	after, afterErr := future.Get(context.Background())
+
+	// assert
+	assert.True(t, errors.Is(err, ErrTaskTimeout))
+	assert.Equal(t, 0, value)
+	assert.Equal(t, 0, after)
+	assert.Equal(t, err, afterErr)
+}
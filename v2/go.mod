@@ -1,10 +1,11 @@
 module github.com/eleniums/async/v2
 
-go 1.11
+go 1.21
+
+require github.com/stretchr/testify v1.4.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/stretchr/testify v1.4.0
-	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v2 v2.2.5 // indirect
 )
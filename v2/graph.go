@@ -0,0 +1,267 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrDependencyFailed is the error of a node that was skipped because a node it depends on did not succeed.
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// NodeStatus is the outcome of a node in a graph.
+type NodeStatus int
+
+const (
+	// NodeSkipped means the node was never started, either because a dependency did not succeed, the context was cancelled or the pool could not give it capacity.
+	NodeSkipped NodeStatus = iota
+
+	// NodeSucceeded means the node's task returned without an error.
+	NodeSucceeded
+
+	// NodeFailed means the node's task returned an error.
+	NodeFailed
+)
+
+// String returns the name of the status.
+func (s NodeStatus) String() string {
+	switch s {
+	case NodeSkipped:
+		return "skipped"
+	case NodeSucceeded:
+		return "succeeded"
+	case NodeFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// NodeResult is the outcome of a single node in a graph.
+type NodeResult struct {
+	// Status is how the node finished.
+	Status NodeStatus
+
+	// Err is the error returned by the node's task, or why the node was skipped.
+	Err error
+
+	// Duration is how long the node's task took to run.
+	Duration time.Duration
+}
+
+// CycleError is returned when the dependencies of a graph form a cycle.
+type CycleError struct {
+	// Path is the names of the nodes in the cycle, starting and ending with the same node.
+	Path []string
+}
+
+// Error returns a description of the cycle.
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// Graph is a set of named tasks that depend on each other. Each task is started as soon as all of its dependencies have succeeded.
+type Graph struct {
+	nodes map[string]*node
+	order []string
+}
+
+// node is a named task in a graph.
+type node struct {
+	name string
+	task TaskContext
+	deps []string
+}
+
+// NewGraph creates a new empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]*node),
+	}
+}
+
+// Add adds a named task that will only be started once the named dependencies have succeeded. Dependencies may be added after the nodes that depend on them.
+func (g *Graph) Add(name string, task TaskContext, deps ...string) error {
+	if _, ok := g.nodes[name]; ok {
+		return fmt.Errorf("node %q already exists", name)
+	}
+
+	g.nodes[name] = &node{
+		name: name,
+		task: task,
+		deps: deps,
+	}
+	g.order = append(g.order, name)
+
+	return nil
+}
+
+// Validate checks that every dependency exists and that there are no cycles, returning a *CycleError if there is one.
+func (g *Graph) Validate() error {
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			if _, ok := g.nodes[dep]; !ok {
+				return fmt.Errorf("node %q depends on unknown node %q", name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(g.nodes))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			cycle := append(append([]string(nil), path[start:]...), name)
+			return &CycleError{Path: cycle}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.nodes[name].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range g.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run validates the graph and executes its tasks with as much parallelism as the dependencies allow, using the pool to limit concurrency if it is not nil. Nodes that depend on a node that did not succeed are skipped. Returns the result of every node along with every failure joined together.
+func (g *Graph) Run(ctx context.Context, pool *TaskPool) (map[string]NodeResult, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	// count the dependencies each node is waiting on
+	remaining := make(map[string]int, len(g.nodes))
+	dependents := make(map[string][]string, len(g.nodes))
+	for _, name := range g.order {
+		n := g.nodes[name]
+		remaining[name] = len(n.deps)
+		for _, dep := range n.deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	type completion struct {
+		name   string
+		result NodeResult
+	}
+
+	results := make(map[string]NodeResult, len(g.nodes))
+	done := make(chan completion, len(g.nodes))
+
+	start := func(n *node) {
+		if ctx.Err() != nil {
+			done <- completion{name: n.name, result: NodeResult{Status: NodeSkipped, Err: ctx.Err()}}
+			return
+		}
+
+		begin := time.Now()
+		finish := func(err error) {
+			result := NodeResult{Status: NodeSucceeded, Err: err, Duration: time.Since(begin)}
+			if err != nil {
+				result.Status = NodeFailed
+			}
+			done <- completion{name: n.name, result: result}
+		}
+
+		// name the task after its node
+		ctx := WithTaskName(ctx, n.name)
+		if pool == nil {
+			go func() {
+				finish(defaultRunner.opts.execute(ctx, TaskInfo{TaskName: n.name}, 0, n.task))
+			}()
+			return
+		}
+
+		// a node that never gets capacity in the pool did not run, so it is skipped rather than failed
+		var ran atomic.Bool
+		errc := pool.RunContext(ctx, func(ctx context.Context) error {
+			ran.Store(true)
+			return n.task(ctx)
+		})
+		go func() {
+			err := Wait(errc)
+			if !ran.Load() {
+				done <- completion{name: n.name, result: NodeResult{Status: NodeSkipped, Err: err}}
+				return
+			}
+			finish(err)
+		}()
+	}
+
+	// skip marks a node and all of its dependents as skipped
+	var skip func(name string, err error)
+	skip = func(name string, err error) {
+		if _, ok := results[name]; ok {
+			return
+		}
+		results[name] = NodeResult{Status: NodeSkipped, Err: err}
+		for _, dependent := range dependents[name] {
+			skip(dependent, err)
+		}
+	}
+
+	for _, name := range g.order {
+		if remaining[name] == 0 {
+			start(g.nodes[name])
+		}
+	}
+
+	for len(results) < len(g.nodes) {
+		c := <-done
+		results[c.name] = c.result
+
+		for _, dependent := range dependents[c.name] {
+			if c.result.Status != NodeSucceeded {
+				skip(dependent, fmt.Errorf("%w: %s", ErrDependencyFailed, c.name))
+				continue
+			}
+
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				if _, ok := results[dependent]; !ok {
+					start(g.nodes[dependent])
+				}
+			}
+		}
+	}
+
+	var errs []error
+	for _, name := range g.order {
+		if r := results[name]; r.Status == NodeFailed {
+			errs = append(errs, fmt.Errorf("%s: %w", name, r.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,239 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Graph_Run_Success(t *testing.T) {
+	// arrange
+	var mu sync.Mutex
+	var order []string
+	step := func(name string) TaskContext {
+		return func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 10)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("server", step("server"), "db", "cache"))
+	assert.NoError(t, graph.Add("config", step("config")))
+	assert.NoError(t, graph.Add("db", step("db"), "config"))
+	assert.NoError(t, graph.Add("cache", step("cache"), "config"))
+
+	// act
+	results, err := graph.Run(context.Background(), nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		assert.Equal(t, NodeSucceeded, result.Status)
+	}
+	assert.Equal(t, "config", order[0])
+	assert.ElementsMatch(t, []string{"db", "cache"}, order[1:3])
+	assert.Equal(t, "server", order[3])
+}
+
+func Test_Graph_Run_Parallel(t *testing.T) {
+	// arrange
+	var running, peak int32
+	task := func(ctx context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 20)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	graph := NewGraph()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, graph.Add(name, task))
+	}
+
+	// act
+	_, err := graph.Run(context.Background(), NewTaskPool(2))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), peak)
+}
+
+func Test_Graph_Run_SkipsDependents(t *testing.T) {
+	// arrange
+	errConfig := errors.New("config error")
+	ok := func(ctx context.Context) error { return nil }
+
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("config", func(ctx context.Context) error { return errConfig }))
+	assert.NoError(t, graph.Add("db", ok, "config"))
+	assert.NoError(t, graph.Add("server", ok, "db"))
+	assert.NoError(t, graph.Add("metrics", ok))
+
+	// act
+	results, err := graph.Run(context.Background(), nil)
+
+	// assert
+	assert.True(t, errors.Is(err, errConfig))
+	assert.Equal(t, NodeFailed, results["config"].Status)
+	assert.Equal(t, NodeSkipped, results["db"].Status)
+	assert.True(t, errors.Is(results["db"].Err, ErrDependencyFailed))
+	assert.Equal(t, NodeSkipped, results["server"].Status)
+	assert.Equal(t, NodeSucceeded, results["metrics"].Status)
+}
+
+func Test_Graph_Run_TaskName(t *testing.T) {
+	// arrange
+	tracer := &recordingTracer{}
+	pool := NewTaskPool(2, WithName("init"), WithTracer(tracer))
+
+	var name string
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("config", func(ctx context.Context) error {
+		name = TaskName(ctx)
+		return nil
+	}))
+
+	// act
+	_, err := graph.Run(context.Background(), pool)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "config", name)
+	assert.Len(t, tracer.starts, 1)
+	assert.Equal(t, "init", tracer.starts[0].Task.Name)
+	assert.Equal(t, "config", tracer.starts[0].Task.TaskName)
+}
+
+func Test_Graph_Run_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("first", func(ctx context.Context) error {
+		cancel()
+		return nil
+	}))
+	assert.NoError(t, graph.Add("second", func(ctx context.Context) error { return nil }, "first"))
+
+	// act
+	results, err := graph.Run(ctx, nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, NodeSucceeded, results["first"].Status)
+	assert.Equal(t, NodeSkipped, results["second"].Status)
+	assert.Equal(t, context.Canceled, results["second"].Err)
+}
+
+func Test_Graph_Run_PoolClosed(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+	pool.Close()
+
+	ok := func(ctx context.Context) error { return nil }
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("config", ok))
+	assert.NoError(t, graph.Add("db", ok, "config"))
+
+	// act
+	results, err := graph.Run(context.Background(), pool)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, NodeSkipped, results["config"].Status)
+	assert.True(t, errors.Is(results["config"].Err, ErrPoolClosed))
+	assert.Equal(t, NodeSkipped, results["db"].Status)
+	assert.True(t, errors.Is(results["db"].Err, ErrDependencyFailed))
+}
+
+func Test_Graph_Run_CancelWaitingForPool(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	busy := pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("config", func(ctx context.Context) error { return nil }))
+
+	// act
+	go func() {
+		for pool.Stats().Waiting != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	results, err := graph.Run(ctx, pool)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, NodeSkipped, results["config"].Status)
+	assert.Equal(t, context.Canceled, results["config"].Err)
+
+	close(release)
+	assert.NoError(t, Wait(busy))
+}
+
+func Test_Graph_Add_Duplicate(t *testing.T) {
+	// arrange
+	graph := NewGraph()
+	task := func(ctx context.Context) error { return nil }
+	assert.NoError(t, graph.Add("a", task))
+
+	// act
+	err := graph.Add("a", task)
+
+	// assert
+	assert.Error(t, err)
+}
+
+func Test_Graph_Validate_UnknownDependency(t *testing.T) {
+	// arrange
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("a", func(ctx context.Context) error { return nil }, "missing"))
+
+	// act
+	err := graph.Validate()
+
+	// assert
+	assert.EqualError(t, err, `node "a" depends on unknown node "missing"`)
+}
+
+func Test_Graph_Validate_Cycle(t *testing.T) {
+	// arrange
+	task := func(ctx context.Context) error { return nil }
+
+	graph := NewGraph()
+	assert.NoError(t, graph.Add("a", task))
+	assert.NoError(t, graph.Add("b", task, "a", "d"))
+	assert.NoError(t, graph.Add("c", task, "b"))
+	assert.NoError(t, graph.Add("d", task, "c"))
+
+	// act
+	_, err := graph.Run(context.Background(), nil)
+
+	// assert
+	var cycleErr *CycleError
+	assert.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []string{"b", "d", "c", "b"}, cycleErr.Path)
+}
@@ -0,0 +1,99 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs tasks that share a context which is cancelled as soon as any of them fails.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	pool   *TaskPool
+
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	count int
+	err   error
+
+	doneOnce sync.Once
+	done     chan error
+}
+
+// NewGroup creates a new group and a context derived from ctx that is given to its tasks. The context is cancelled when a task fails or Wait returns.
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	return NewGroupWithPool(ctx, nil)
+}
+
+// NewGroupWithPool creates a new group that runs its tasks on the given pool to limit how many are running at once. A nil pool places no limit on the group.
+func NewGroupWithPool(ctx context.Context, pool *TaskPool) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		pool:   pool,
+	}, ctx
+}
+
+// Go will execute the given task concurrently. If the group has a pool, Go will block until there is available capacity.
+func (g *Group) Go(task TaskContext) {
+	g.mu.Lock()
+	info := TaskInfo{TaskName: TaskName(g.ctx), Index: g.count}
+	g.count++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+
+	if g.pool == nil {
+		go func() {
+			defer g.wg.Done()
+			g.fail(defaultRunner.opts.executeTask(g.ctx, info, 0, task))
+		}()
+		return
+	}
+
+	errc := g.pool.RunContext(g.ctx, task)
+	go func() {
+		defer g.wg.Done()
+		g.fail(Wait(errc))
+	}()
+}
+
+// Wait until all tasks have finished and return the first error, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// Done returns a channel that receives the first error, if any, and is closed once all tasks have finished. The channel can be used with Wait and HandleError.
+func (g *Group) Done() <-chan error {
+	g.doneOnce.Do(func() {
+		g.done = make(chan error, 1)
+		go func() {
+			defer close(g.done)
+			if err := g.Wait(); err != nil {
+				g.done <- err
+			}
+		}()
+	})
+
+	return g.done
+}
+
+// fail records the first error and cancels the remaining tasks.
+func (g *Group) fail(err error) {
+	if err == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil {
+		g.err = err
+		g.cancel()
+	}
+}
@@ -0,0 +1,110 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Group_Success(t *testing.T) {
+	// arrange
+	group, _ := NewGroup(context.Background())
+
+	var count int32
+	task := func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+
+	// act
+	group.Go(task)
+	group.Go(task)
+	group.Go(task)
+	err := group.Wait()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), count)
+}
+
+func Test_Group_Error_CancelsSiblings(t *testing.T) {
+	// arrange
+	group, ctx := NewGroup(context.Background())
+
+	errTask := errors.New("task error")
+	task1 := func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 50)
+		return errTask
+	}
+
+	var cancelled int32
+	task2 := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			atomic.AddInt32(&cancelled, 1)
+			return ctx.Err()
+		case <-time.After(time.Second * 5):
+			return nil
+		}
+	}
+
+	// act
+	group.Go(task1)
+	group.Go(task2)
+	group.Go(task2)
+	err := group.Wait()
+
+	// assert
+	assert.Equal(t, errTask, err)
+	assert.Equal(t, int32(2), cancelled)
+	assert.Error(t, ctx.Err())
+}
+
+func Test_Group_WithPool_LimitsConcurrency(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+	group, _ := NewGroupWithPool(context.Background(), pool)
+
+	var running, peak int32
+	task := func(ctx context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 20)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	// act
+	for i := 0; i < 6; i++ {
+		group.Go(task)
+	}
+	err := group.Wait()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), peak)
+}
+
+func Test_Group_Done_Error(t *testing.T) {
+	// arrange
+	group, _ := NewGroup(context.Background())
+
+	group.Go(func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+
+	// act
+	err := Wait(group.Done())
+
+	// assert
+	assert.Error(t, err)
+}
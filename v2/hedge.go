@@ -0,0 +1,173 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HedgeOption configures how Hedge starts its attempts.
+type HedgeOption func(*hedgeOptions)
+
+// hedgeOptions holds the settings used by Hedge.
+type hedgeOptions struct {
+	pool   *TaskPool
+	budget *HedgeBudget
+}
+
+// WithHedgePool runs every attempt, including the first, in the given pool so that hedges count against its capacity.
+func WithHedgePool(pool *TaskPool) HedgeOption {
+	return func(o *hedgeOptions) {
+		o.pool = pool
+	}
+}
+
+// WithHedgeBudget limits the hedges that are started to those allowed by the given budget, which can be shared between many calls to Hedge.
+func WithHedgeBudget(budget *HedgeBudget) HedgeOption {
+	return func(o *hedgeOptions) {
+		o.budget = budget
+	}
+}
+
+// HedgeBudget limits hedged attempts to a percentage of the calls made to Hedge, so that hedging does not amplify load when a dependency is slow for every caller. Each call earns a fraction of a hedge and each hedge spends a whole one, up to a maximum of burst hedges saved.
+type HedgeBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+// NewHedgeBudget creates a budget that allows hedges for up to percent of calls to Hedge, with up to burst hedges available at once. The budget starts full.
+func NewHedgeBudget(percent float64, burst int) *HedgeBudget {
+	if percent < 0 {
+		percent = 0
+	}
+	if burst < 0 {
+		burst = 0
+	}
+
+	return &HedgeBudget{
+		ratio:  percent / 100,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// Available returns the number of hedges that can currently be started.
+func (b *HedgeBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return int(b.tokens)
+}
+
+// deposit credits the budget for a call to Hedge.
+func (b *HedgeBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// withdraw spends a hedge from the budget and reports whether one was available.
+func (b *HedgeBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Hedge will execute the given function and, each time delay passes without an answer, start a duplicate attempt, up to maxHedges duplicates. The first successful result is returned and the context given to the remaining attempts is cancelled. If every attempt that was started fails, the first error is returned. The function must be safe to run more than once at the same time, such as an idempotent read.
+func Hedge[T any](ctx context.Context, fn func(ctx context.Context) (T, error), delay time.Duration, maxHedges int, opts ...HedgeOption) (T, error) {
+	if maxHedges < 0 {
+		panic("maxHedges must be a value of >= 0")
+	}
+
+	var o hedgeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.budget != nil {
+		o.budget.deposit()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that attempts finishing after Hedge returns are not blocked
+	results := make(chan *Future[T], maxHedges+1)
+	start := func() {
+		// started on its own goroutine so that waiting for pool capacity doesn't stop results from being received
+		go func() {
+			f := hedgeAttempt(ctx, o.pool, fn)
+			<-f.Done()
+			results <- f
+		}()
+	}
+
+	start()
+	started, pending := 1, 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	hedges := timer.C
+	if maxHedges == 0 {
+		hedges = nil
+	}
+
+	var firstErr error
+	for {
+		select {
+		case f := <-results:
+			if f.err == nil {
+				return f.value, nil
+			}
+			if firstErr == nil {
+				firstErr = f.err
+			}
+			pending--
+			if pending == 0 {
+				var zero T
+				return zero, firstErr
+			}
+		case <-hedges:
+			if o.budget != nil && !o.budget.withdraw() {
+				hedges = nil
+				continue
+			}
+
+			start()
+			started++
+			pending++
+			if started > maxHedges {
+				hedges = nil
+				continue
+			}
+			timer.Reset(delay)
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// hedgeAttempt starts a single attempt of a hedged function, in the pool if one is given.
+func hedgeAttempt[T any](ctx context.Context, pool *TaskPool, fn func(ctx context.Context) (T, error)) *Future[T] {
+	if pool != nil {
+		return SubmitContext(ctx, pool, fn)
+	}
+
+	f := newFuture[T]()
+	errc := RunContext(ctx, func(ctx context.Context) error {
+		return f.task(func() (T, error) { return fn(ctx) })()
+	})
+	go f.complete(errc)
+	return f
+}
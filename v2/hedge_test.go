@@ -0,0 +1,242 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Hedge_Success(t *testing.T) {
+	// arrange
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		return 42, nil
+	}
+
+	// act
+	result, err := Hedge(context.Background(), fn, time.Second, 2)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func Test_Hedge_SlowPrimary(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+	var attempts atomic.Int32
+	var cancelled atomic.Bool
+	fn := func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			cancelled.Store(true)
+			return 0, ctx.Err()
+		}
+		return 2, nil
+	}
+
+	// act
+	result, err := Hedge(context.Background(), fn, time.Millisecond*10, 1)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result)
+	assert.Equal(t, int32(2), attempts.Load())
+	assertEventually(t, cancelled.Load)
+	assertGoroutines(t, before)
+}
+
+func Test_Hedge_MaxHedges(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	// act
+	_, err := Hedge(ctx, fn, time.Millisecond, 3)
+
+	// assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(4), attempts.Load())
+	assertGoroutines(t, before)
+}
+
+func Test_Hedge_AllFail(t *testing.T) {
+	// arrange
+	first := errors.New("first")
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			time.Sleep(time.Millisecond * 20)
+			return 0, first
+		}
+		time.Sleep(time.Millisecond * 40)
+		return 0, errors.New("second")
+	}
+
+	// act
+	_, err := Hedge(context.Background(), fn, time.Millisecond, 1)
+
+	// assert
+	assert.Equal(t, first, err)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func Test_Hedge_FailBeforeDelay(t *testing.T) {
+	// arrange
+	expected := errors.New("task error")
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		return 0, expected
+	}
+
+	// act
+	_, err := Hedge(context.Background(), fn, time.Second, 2)
+
+	// assert
+	assert.Equal(t, expected, err)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func Test_Hedge_Panic(t *testing.T) {
+	// act
+	_, err := Hedge(context.Background(), func(ctx context.Context) (int, error) {
+		panic("oops")
+	}, time.Second, 1)
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "oops", panicErr.Value)
+}
+
+func Test_Hedge_Budget(t *testing.T) {
+	// arrange
+	budget := NewHedgeBudget(10, 1)
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		if attempts.Add(1)%2 == 1 {
+			time.Sleep(time.Millisecond * 20)
+		}
+		return 1, nil
+	}
+
+	// act
+	_, err1 := Hedge(context.Background(), fn, time.Millisecond, 1, WithHedgeBudget(budget))
+	hedged := attempts.Load()
+	_, err2 := Hedge(context.Background(), fn, time.Millisecond, 1, WithHedgeBudget(budget))
+
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, int32(2), hedged)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, 0, budget.Available())
+}
+
+func Test_HedgeBudget_Deposit(t *testing.T) {
+	// arrange
+	budget := NewHedgeBudget(50, 2)
+	budget.withdraw()
+	budget.withdraw()
+
+	// act
+	for i := 0; i < 10; i++ {
+		budget.deposit()
+	}
+
+	// assert
+	assert.Equal(t, 2, budget.Available())
+	assert.True(t, budget.withdraw())
+	assert.True(t, budget.withdraw())
+	assert.False(t, budget.withdraw())
+}
+
+func Test_Hedge_Pool(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return 2, nil
+	}
+
+	// act
+	result, err := Hedge(context.Background(), fn, time.Millisecond*10, 1, WithHedgePool(pool))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result)
+	assert.NoError(t, pool.Wait())
+	assert.Equal(t, uint64(2), pool.Stats().Started)
+}
+
+func Test_Hedge_Invalid(t *testing.T) {
+	// assert
+	assert.Panics(t, func() {
+		Hedge(context.Background(), func(ctx context.Context) (int, error) { return 0, nil }, time.Second, -1)
+	})
+}
+
+func Test_Hedge_SaturatedPool(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+	started := make(chan struct{})
+	var attempts atomic.Int32
+	fn := func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			close(started)
+			time.Sleep(time.Millisecond * 50)
+			return 1, nil
+		}
+		return 2, nil
+	}
+
+	// act
+	type result struct {
+		value   int
+		err     error
+		elapsed time.Duration
+	}
+	done := make(chan result, 1)
+	go func() {
+		start := time.Now()
+		v, err := Hedge(context.Background(), fn, time.Millisecond*20, 1, WithHedgePool(pool))
+		done <- result{v, err, time.Since(start)}
+	}()
+
+	// queue work ahead of the hedge so it can't get capacity before the first attempt finishes
+	<-started
+	for i := 0; i < 3; i++ {
+		pool.Run(context.Background(), func() error {
+			time.Sleep(time.Millisecond * 100)
+			return nil
+		})
+	}
+	r := <-done
+
+	// assert
+	assert.NoError(t, r.err)
+	assert.Equal(t, 1, r.value)
+	assert.Less(t, int64(r.elapsed), int64(time.Millisecond*100))
+	assert.NoError(t, pool.Wait())
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -0,0 +1,126 @@
+package async
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket that limits how many tasks are executed per second. Tokens are added at a steady rate and up to burst tokens may be saved up for tasks to use at once. The rate and burst can be changed while the limiter is in use.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	tokens  float64
+	last    time.Time
+	changed chan struct{}
+}
+
+// NewLimiter creates a new limiter that allows rate tasks per second with bursts of up to burst tasks. The bucket starts full.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		tokens:  float64(burst),
+		last:    time.Now(),
+		changed: make(chan struct{}),
+	}
+}
+
+// Wait will block until a token is available or the context is cancelled. A rate of zero or less blocks until the rate is raised, and a rate of math.Inf(1) never blocks.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		l.advance(time.Now())
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		// wait for the next token, or indefinitely if no tokens are being added
+		var timer *time.Timer
+		var ready <-chan time.Time
+		if l.rate > 0 {
+			timer = time.NewTimer(time.Duration((1 - l.tokens) / l.rate * float64(time.Second)))
+			ready = timer.C
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-changed:
+		case <-ctx.Done():
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// SetRate changes the number of tasks allowed per second. Callers waiting for a token are woken up to use the new rate.
+func (l *Limiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.rate = rate
+	l.notify()
+}
+
+// SetBurst changes the number of tokens that may be saved up.
+func (l *Limiter) SetBurst(burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.burst = burst
+	l.tokens = math.Min(l.tokens, float64(burst))
+	l.notify()
+}
+
+// Rate returns the number of tasks allowed per second.
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Burst returns the number of tokens that may be saved up.
+func (l *Limiter) Burst() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.burst
+}
+
+// advance adds the tokens accumulated since the last update.
+func (l *Limiter) advance(now time.Time) {
+	switch {
+	case math.IsInf(l.rate, 1):
+		l.tokens = float64(l.burst)
+	case l.rate > 0:
+		l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.rate, float64(l.burst))
+	}
+	l.last = now
+}
+
+// notify wakes up all callers waiting for a token.
+func (l *Limiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
@@ -0,0 +1,117 @@
+package async
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_NewLimiter_Success(t *testing.T) {
+	// act
+	limiter := NewLimiter(10, 0)
+
+	// assert
+	assert.Equal(t, float64(10), limiter.Rate())
+	assert.Equal(t, 1, limiter.Burst())
+}
+
+func Test_Limiter_Wait_Burst(t *testing.T) {
+	// arrange
+	limiter := NewLimiter(1, 5)
+
+	// act
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// assert
+	assert.Less(t, int64(time.Since(start)), int64(time.Millisecond*100))
+}
+
+func Test_Limiter_Wait_Cancel(t *testing.T) {
+	// arrange
+	limiter := NewLimiter(0, 1)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// act
+	err := limiter.Wait(ctx)
+
+	// assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func Test_Limiter_SetRate_WakesWaiters(t *testing.T) {
+	// arrange
+	limiter := NewLimiter(0, 1)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	done := make(chan error)
+	go func() {
+		done <- limiter.Wait(context.Background())
+	}()
+
+	// act
+	time.Sleep(time.Millisecond * 50)
+	limiter.SetRate(math.Inf(1))
+
+	// assert
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "waiter was not woken by the new rate")
+	}
+}
+
+func Test_WithRateLimit_RunLimited(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithRateLimit(NewLimiter(100, 1)))
+
+	var count int32
+	task := func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+
+	// act
+	start := time.Now()
+	errc := runner.RunLimited(context.Background(), 4, 5, task)
+	err := Wait(errc)
+	elapsed := time.Since(start)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(20), count)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(time.Millisecond*180))
+}
+
+func Test_WithRateLimit_RunForever(t *testing.T) {
+	// arrange
+	limiter := NewLimiter(50, 1)
+	runner := NewRunner(WithRateLimit(limiter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+
+	var count int32
+	task := func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+
+	// act
+	errc := runner.RunForever(ctx, 8, task)
+	err := Wait(errc)
+
+	// assert
+	assert.Error(t, err)
+	assert.LessOrEqual(t, atomic.LoadInt32(&count), int32(15))
+}
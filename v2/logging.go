@@ -0,0 +1,180 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// LogEvent is a point in the lifecycle of a task that can be logged.
+type LogEvent int
+
+const (
+	// LogQueued is logged when a task starts waiting for capacity in a TaskPool or for the rate limiter of a Runner.
+	LogQueued LogEvent = iota
+
+	// LogStarted is logged when a task starts running.
+	LogStarted
+
+	// LogFinished is logged when a task returns without an error.
+	LogFinished
+
+	// LogFailed is logged when a task returns an error.
+	LogFailed
+
+	// LogPanicked is logged when a task panics.
+	LogPanicked
+
+	// LogCancelled is logged when a task stops because its context was cancelled.
+	LogCancelled
+
+	// numLogEvents is the number of log events.
+	numLogEvents
+)
+
+// String returns the name of the event.
+func (e LogEvent) String() string {
+	switch e {
+	case LogQueued:
+		return "queued"
+	case LogStarted:
+		return "started"
+	case LogFinished:
+		return "finished"
+	case LogFailed:
+		return "failed"
+	case LogPanicked:
+		return "panicked"
+	case LogCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultLogLevels are the levels each event is logged at unless changed with WithLogLevel.
+var defaultLogLevels = [numLogEvents]slog.Level{
+	LogQueued:    slog.LevelDebug,
+	LogStarted:   slog.LevelDebug,
+	LogFinished:  slog.LevelDebug,
+	LogFailed:    slog.LevelWarn,
+	LogPanicked:  slog.LevelError,
+	LogCancelled: slog.LevelInfo,
+}
+
+// taskLogger logs the lifecycle events of tasks.
+type taskLogger struct {
+	logger *slog.Logger
+	levels [numLogEvents]slog.Level
+	sample uint64
+	tasks  atomic.Uint64
+}
+
+// logSampledKey is the context key for whether the events of a task are logged.
+type logSampledKey struct{}
+
+// WithLogger logs the lifecycle events of every task executed by a Runner or TaskPool. Every entry has the name of the runner or pool, the name given to the task with WithTaskName and the task index, along with the duration and error where they apply.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger().logger = l
+	}
+}
+
+// WithLogLevel changes the level an event is logged at.
+func WithLogLevel(event LogEvent, level slog.Level) Option {
+	return func(o *options) {
+		o.logger().levels[event] = level
+	}
+}
+
+// WithLogSampling logs the events of only one out of every n tasks, so that tasks executed in a tight loop do not flood the logs. A task is sampled when it is queued and every later event for it is logged, so a sampled task can be followed through its lifecycle. Panics are always logged.
+func WithLogSampling(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.logger().sample = uint64(n)
+	}
+}
+
+// logger returns the task logger, creating it if needed.
+func (o *options) logger() *taskLogger {
+	if o.log == nil {
+		o.log = &taskLogger{
+			levels: defaultLogLevels,
+			sample: 1,
+		}
+	}
+	return o.log
+}
+
+// logQueued decides whether the task is sampled and logs that it is waiting to be executed. The returned context carries the decision to the task's later events.
+func (o *options) logQueued(ctx context.Context, info TaskInfo) context.Context {
+	l := o.log
+	if l == nil || l.logger == nil || l.sample == 1 {
+		o.log.write(ctx, LogQueued, info)
+		return ctx
+	}
+
+	sampled := l.tasks.Add(1)%l.sample == 1
+	ctx = context.WithValue(ctx, logSampledKey{}, sampled)
+	o.log.write(ctx, LogQueued, info)
+	return ctx
+}
+
+// logStarted logs that a task has started running.
+func (o *options) logStarted(ctx context.Context, info TaskInfo, wait time.Duration) {
+	o.log.write(ctx, LogStarted, info, slog.Duration("wait", wait))
+}
+
+// logResult logs how a task that ran for the given duration finished.
+func (o *options) logResult(ctx context.Context, info TaskInfo, duration time.Duration, err error) {
+	if o.log == nil {
+		return
+	}
+
+	event := LogFinished
+	attrs := []slog.Attr{slog.Duration("duration", duration)}
+
+	var panicErr *PanicError
+	switch {
+	case err == nil:
+	case errors.As(err, &panicErr):
+		event = LogPanicked
+		attrs = append(attrs, slog.Any("error", err), slog.String("stack", string(panicErr.Stack)))
+	case ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		event = LogCancelled
+		attrs = append(attrs, slog.Any("error", err))
+	default:
+		event = LogFailed
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	o.log.write(ctx, event, info, attrs...)
+}
+
+// write logs an event for the task if it is sampled.
+func (l *taskLogger) write(ctx context.Context, event LogEvent, info TaskInfo, attrs ...slog.Attr) {
+	if l == nil || l.logger == nil {
+		return
+	}
+
+	level := l.levels[event]
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	if sampled, ok := ctx.Value(logSampledKey{}).(bool); ok && !sampled && event != LogPanicked {
+		return
+	}
+
+	attrs = append([]slog.Attr{
+		slog.String("name", info.Name),
+		slog.String("task_name", info.TaskName),
+		slog.Int("task", info.Index),
+		slog.Int("iteration", info.Iteration),
+	}, attrs...)
+	l.logger.LogAttrs(ctx, level, "task "+event.String(), attrs...)
+}
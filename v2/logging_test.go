@@ -0,0 +1,198 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// recordingHandler records every log entry it handles.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return h
+}
+
+// messages returns the message and level of every record.
+func (h *recordingHandler) messages() map[string]slog.Level {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages := make(map[string]slog.Level)
+	for _, r := range h.records {
+		messages[r.Message] = r.Level
+	}
+	return messages
+}
+
+// attrs returns the attributes of the first record with the given message.
+func (h *recordingHandler) attrs(message string) map[string]slog.Value {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	attrs := make(map[string]slog.Value)
+	for _, r := range h.records {
+		if r.Message == message {
+			r.Attrs(func(a slog.Attr) bool {
+				attrs[a.Key] = a.Value
+				return true
+			})
+			break
+		}
+	}
+	return attrs
+}
+
+func Test_WithLogger_Runner(t *testing.T) {
+	// arrange
+	handler := &recordingHandler{}
+	runner := NewRunner(WithName("loader"), WithLogger(slog.New(handler)))
+
+	task1 := func(ctx context.Context) error {
+		return nil
+	}
+
+	task2 := func(ctx context.Context) error {
+		return errors.New("task error")
+	}
+
+	task3 := func(ctx context.Context) error {
+		panic("task panic")
+	}
+
+	// act
+	WaitAll(runner.Run(context.Background(), task1, task2, task3))
+
+	// assert
+	messages := handler.messages()
+	assert.Equal(t, slog.LevelDebug, messages["task queued"])
+	assert.Equal(t, slog.LevelDebug, messages["task started"])
+	assert.Equal(t, slog.LevelDebug, messages["task finished"])
+	assert.Equal(t, slog.LevelWarn, messages["task failed"])
+	assert.Equal(t, slog.LevelError, messages["task panicked"])
+
+	attrs := handler.attrs("task failed")
+	assert.Equal(t, "loader", attrs["name"].String())
+	assert.Equal(t, int64(1), attrs["task"].Int64())
+	assert.Contains(t, attrs, "duration")
+	assert.Contains(t, attrs["error"].String(), "task error")
+	assert.Contains(t, handler.attrs("task panicked"), "stack")
+}
+
+func Test_WithLogger_TaskPool_Cancelled(t *testing.T) {
+	// arrange
+	handler := &recordingHandler{}
+	pool := NewTaskPool(1, WithName("images"), WithLogger(slog.New(handler)), WithLogLevel(LogCancelled, slog.LevelWarn))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan bool)
+	errc := pool.RunContext(ctx, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+
+	// act
+	cancel()
+	<-errc
+
+	// assert
+	messages := handler.messages()
+	assert.Equal(t, slog.LevelWarn, messages["task cancelled"])
+	assert.Equal(t, "images", handler.attrs("task cancelled")["name"].String())
+}
+
+func Test_WithLogger_TaskName(t *testing.T) {
+	// arrange
+	handler := &recordingHandler{}
+	runner := NewRunner(WithName("loader"), WithLogger(slog.New(handler)))
+
+	// act
+	WaitAll(runner.Run(WithTaskName(context.Background(), "config"), func(ctx context.Context) error {
+		return errors.New("task error")
+	}))
+
+	// assert
+	attrs := handler.attrs("task failed")
+	assert.Equal(t, "loader", attrs["name"].String())
+	assert.Equal(t, "config", attrs["task_name"].String())
+}
+
+func Test_WithLogSampling_Success(t *testing.T) {
+	// arrange
+	handler := &recordingHandler{}
+	runner := NewRunner(WithLogger(slog.New(handler)), WithLogSampling(10))
+
+	task := func(ctx context.Context) error {
+		return nil
+	}
+
+	// act
+	Wait(runner.RunLimited(context.Background(), 1, 25, task))
+
+	// assert
+	count := 0
+	for _, r := range handler.records {
+		if r.Message == "task finished" {
+			count++
+		}
+	}
+	assert.Equal(t, 3, count)
+}
+
+func Test_WithLogSampling_WholeTask(t *testing.T) {
+	// arrange
+	handler := &recordingHandler{}
+	runner := NewRunner(WithLogger(slog.New(handler)), WithLogSampling(3))
+
+	var calls atomic.Int32
+	task := func(ctx context.Context) error {
+		if calls.Add(1)%2 == 0 {
+			return errors.New("task error")
+		}
+		return nil
+	}
+
+	// act
+	WaitAll(runner.RunLimited(context.Background(), 1, 9, task))
+
+	// assert
+	iterations := make(map[string][]int64)
+	for _, r := range handler.records {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == "iteration" {
+				iterations[r.Message] = append(iterations[r.Message], a.Value.Int64())
+			}
+			return true
+		})
+	}
+	assert.Equal(t, []int64{0, 3, 6}, iterations["task queued"])
+	assert.Equal(t, []int64{0, 3, 6}, iterations["task started"])
+	assert.Equal(t, []int64{0, 6}, iterations["task finished"])
+	assert.Equal(t, []int64{3}, iterations["task failed"])
+}
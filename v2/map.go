@@ -0,0 +1,83 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Map calls fn for every item with at most limit calls running at once and returns the results in the same order as the items. A limit of zero or less runs every call at once. After the first error, or once the context is cancelled, no more calls are started and that error is returned.
+func Map[T any, R any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+
+	err := forEachIndex(ctx, len(items), limit, func(ctx context.Context, i int) error {
+		r, err := fn(ctx, items[i])
+		results[i] = r
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ForEach calls fn for every item with at most limit calls running at once. A limit of zero or less runs every call at once. After the first error, or once the context is cancelled, no more calls are started and that error is returned.
+func ForEach[T any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) error) error {
+	return forEachIndex(ctx, len(items), limit, func(ctx context.Context, i int) error {
+		return fn(ctx, items[i])
+	})
+}
+
+// forEachIndex calls fn for every index from 0 to n on at most limit goroutines, stopping at the first error.
+func forEachIndex(ctx context.Context, n int, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     atomic.Int64
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+
+				if err := ctx.Err(); err != nil {
+					fail(err)
+					return
+				}
+
+				err := defaultRunner.opts.call(ctx, TaskInfo{TaskName: TaskName(ctx), Index: i}, func(ctx context.Context) error {
+					return fn(ctx, i)
+				})
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,119 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Map_Success(t *testing.T) {
+	// arrange
+	items := []int{5, 4, 3, 2, 1}
+
+	// act
+	results, err := Map(context.Background(), items, 2, func(ctx context.Context, item int) (string, error) {
+		time.Sleep(time.Millisecond * time.Duration(item))
+		return strconv.Itoa(item * 10), nil
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"50", "40", "30", "20", "10"}, results)
+}
+
+func Test_Map_Empty(t *testing.T) {
+	// act
+	results, err := Map(context.Background(), nil, 4, func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func Test_Map_Error(t *testing.T) {
+	// arrange
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	errItem := errors.New("item error")
+	var calls int32
+
+	// act
+	results, err := Map(context.Background(), items, 4, func(ctx context.Context, item int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if item == 10 {
+			return 0, errItem
+		}
+		return item, nil
+	})
+
+	// assert
+	assert.Equal(t, errItem, err)
+	assert.Nil(t, results)
+	assert.Less(t, atomic.LoadInt32(&calls), int32(100))
+}
+
+func Test_Map_Panic(t *testing.T) {
+	// act
+	_, err := Map(context.Background(), []int{0, 1, 2}, 0, func(ctx context.Context, item int) (int, error) {
+		if item == 2 {
+			panic("item panic")
+		}
+		return item, nil
+	})
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, 2, panicErr.Task.Index)
+}
+
+func Test_ForEach_Limit(t *testing.T) {
+	// arrange
+	var running, peak int32
+
+	// act
+	err := ForEach(context.Background(), make([]int, 10), 3, func(ctx context.Context, item int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 10)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), peak)
+}
+
+func Test_ForEach_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+
+	// act
+	err := ForEach(ctx, make([]int, 100), 1, func(ctx context.Context, item int) error {
+		if atomic.AddInt32(&calls, 1) == 5 {
+			cancel()
+		}
+		return nil
+	})
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int32(5), calls)
+}
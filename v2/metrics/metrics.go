@@ -0,0 +1,227 @@
+// Package metrics exposes statistics from async task pools and runners in the OpenMetrics text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/eleniums/async/v2"
+)
+
+// ContentType is the content type of the OpenMetrics text format.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Registry collects statistics from named pools and runners. It implements http.Handler so it can be served at a scrape endpoint.
+type Registry struct {
+	mu      sync.Mutex
+	pools   map[string]*async.TaskPool
+	runners map[string]*async.Runner
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		pools:   make(map[string]*async.TaskPool),
+		runners: make(map[string]*async.Runner),
+	}
+}
+
+// RegisterPool adds a pool whose metrics are labelled with the given name, replacing any pool already registered with that name.
+func (r *Registry) RegisterPool(name string, pool *async.TaskPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pools[name] = pool
+}
+
+// UnregisterPool removes the pool registered with the given name.
+func (r *Registry) UnregisterPool(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pools, name)
+}
+
+// RegisterRunner adds a runner whose metrics are labelled with the given name, replacing any runner already registered with that name.
+func (r *Registry) RegisterRunner(name string, runner *async.Runner) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runners[name] = runner
+}
+
+// UnregisterRunner removes the runner registered with the given name.
+func (r *Registry) UnregisterRunner(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.runners, name)
+}
+
+// ServeHTTP writes the metrics of every registered pool and runner.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	r.Write(w)
+}
+
+// Write writes the metrics of every registered pool and runner in the OpenMetrics text format.
+func (r *Registry) Write(w io.Writer) error {
+	pools, runners := r.snapshot()
+
+	b := bufio.NewWriter(w)
+
+	family(b, "async_pool_capacity", "gauge", "Max number of concurrent tasks.")
+	for _, p := range pools {
+		sample(b, "async_pool_capacity", label("pool", p.name), float64(p.max))
+	}
+
+	family(b, "async_pool_in_flight", "gauge", "Number of tasks running.")
+	for _, p := range pools {
+		sample(b, "async_pool_in_flight", label("pool", p.name), float64(p.stats.InFlight))
+	}
+
+	family(b, "async_pool_waiting", "gauge", "Number of callers waiting for capacity.")
+	for _, p := range pools {
+		sample(b, "async_pool_waiting", label("pool", p.name), float64(p.stats.Waiting))
+	}
+
+	family(b, "async_pool_leaked", "gauge", "Number of abandoned tasks still running.")
+	for _, p := range pools {
+		sample(b, "async_pool_leaked", label("pool", p.name), float64(p.stats.Leaked))
+	}
+
+	family(b, "async_pool_tasks_started", "counter", "Number of tasks started.")
+	for _, p := range pools {
+		sample(b, "async_pool_tasks_started_total", label("pool", p.name), float64(p.stats.Started))
+	}
+
+	family(b, "async_pool_tasks_completed", "counter", "Number of tasks completed by result.")
+	for _, p := range pools {
+		results(b, "async_pool_tasks_completed_total", label("pool", p.name), p.stats.Succeeded, p.stats.Failed, p.stats.Panicked)
+	}
+
+	family(b, "async_pool_task_duration_seconds", "histogram", "Time taken to run tasks.")
+	for _, p := range pools {
+		histogram(b, "async_pool_task_duration_seconds", label("pool", p.name), p.stats.Duration)
+	}
+
+	family(b, "async_pool_acquire_wait_seconds", "histogram", "Time spent waiting for capacity.")
+	for _, p := range pools {
+		histogram(b, "async_pool_acquire_wait_seconds", label("pool", p.name), p.stats.AcquireWait)
+	}
+
+	family(b, "async_runner_in_flight", "gauge", "Number of tasks running.")
+	for _, r := range runners {
+		sample(b, "async_runner_in_flight", label("runner", r.name), float64(r.stats.InFlight))
+	}
+
+	family(b, "async_runner_leaked", "gauge", "Number of abandoned tasks still running.")
+	for _, r := range runners {
+		sample(b, "async_runner_leaked", label("runner", r.name), float64(r.stats.Leaked))
+	}
+
+	family(b, "async_runner_tasks_started", "counter", "Number of tasks started.")
+	for _, r := range runners {
+		sample(b, "async_runner_tasks_started_total", label("runner", r.name), float64(r.stats.Started))
+	}
+
+	family(b, "async_runner_tasks_completed", "counter", "Number of tasks completed by result.")
+	for _, r := range runners {
+		results(b, "async_runner_tasks_completed_total", label("runner", r.name), r.stats.Succeeded, r.stats.Failed, r.stats.Panicked)
+	}
+
+	family(b, "async_runner_task_duration_seconds", "histogram", "Time taken to run tasks.")
+	for _, r := range runners {
+		histogram(b, "async_runner_task_duration_seconds", label("runner", r.name), r.stats.Duration)
+	}
+
+	b.WriteString("# EOF\n")
+	return b.Flush()
+}
+
+// poolSnapshot holds the statistics of a registered pool.
+type poolSnapshot struct {
+	name  string
+	max   int
+	stats async.PoolStats
+}
+
+// runnerSnapshot holds the statistics of a registered runner.
+type runnerSnapshot struct {
+	name  string
+	stats async.RunnerStats
+}
+
+// snapshot returns the statistics of every registered pool and runner sorted by name.
+func (r *Registry) snapshot() ([]poolSnapshot, []runnerSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pools := make([]poolSnapshot, 0, len(r.pools))
+	for name, p := range r.pools {
+		pools = append(pools, poolSnapshot{name: name, max: p.Max(), stats: p.Stats()})
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].name < pools[j].name })
+
+	runners := make([]runnerSnapshot, 0, len(r.runners))
+	for name, runner := range r.runners {
+		runners = append(runners, runnerSnapshot{name: name, stats: runner.Stats()})
+	}
+	sort.Slice(runners, func(i, j int) bool { return runners[i].name < runners[j].name })
+
+	return pools, runners
+}
+
+// family writes the metadata of a metric family.
+func family(w *bufio.Writer, name string, typ string, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+}
+
+// sample writes a single sample.
+func sample(w *bufio.Writer, name string, labels string, value float64) {
+	fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(value))
+}
+
+// results writes the completed counts of each result.
+func results(w *bufio.Writer, name string, labels string, succeeded uint64, failed uint64, panicked uint64) {
+	sample(w, name, labels+","+label("result", "succeeded"), float64(succeeded))
+	sample(w, name, labels+","+label("result", "failed"), float64(failed))
+	sample(w, name, labels+","+label("result", "panicked"), float64(panicked))
+}
+
+// histogram writes the cumulative buckets, sum and count of a histogram.
+func histogram(w *bufio.Writer, name string, labels string, h async.Histogram) {
+	var cumulative uint64
+	for i, count := range h.Counts {
+		cumulative += count
+
+		le := "+Inf"
+		if i < len(h.Bounds) {
+			le = formatFloat(h.Bounds[i].Seconds())
+		}
+		sample(w, name+"_bucket", labels+","+label("le", le), float64(cumulative))
+	}
+
+	sample(w, name+"_sum", labels, h.Sum.Seconds())
+	sample(w, name+"_count", labels, float64(h.Count))
+}
+
+// label formats a label with its value escaped.
+func label(name string, value string) string {
+	return name + `="` + labelEscaper.Replace(value) + `"`
+}
+
+// labelEscaper escapes label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat formats a sample value.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
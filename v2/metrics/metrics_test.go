@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eleniums/async/v2"
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Registry_ServeHTTP_Pool(t *testing.T) {
+	// arrange
+	pool := async.NewTaskPool(4)
+	<-pool.Run(context.Background(), func() error { return nil })
+	<-pool.Run(context.Background(), func() error { return errors.New("task error") })
+
+	registry := NewRegistry()
+	registry.RegisterPool("images", pool)
+
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	// act
+	resp, err := server.Client().Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	// assert
+	assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+
+	text := string(body)
+	assert.Contains(t, text, "# TYPE async_pool_capacity gauge\n")
+	assert.Contains(t, text, `async_pool_capacity{pool="images"} 4`+"\n")
+	assert.Contains(t, text, `async_pool_tasks_started_total{pool="images"} 2`+"\n")
+	assert.Contains(t, text, `async_pool_tasks_completed_total{pool="images",result="succeeded"} 1`+"\n")
+	assert.Contains(t, text, `async_pool_tasks_completed_total{pool="images",result="failed"} 1`+"\n")
+	assert.Contains(t, text, `async_pool_task_duration_seconds_bucket{pool="images",le="+Inf"} 2`+"\n")
+	assert.Contains(t, text, `async_pool_task_duration_seconds_count{pool="images"} 2`+"\n")
+	assert.Contains(t, text, `async_pool_acquire_wait_seconds_count{pool="images"} 2`+"\n")
+	assert.True(t, strings.HasSuffix(text, "# EOF\n"))
+}
+
+func Test_Registry_Write_Runner(t *testing.T) {
+	// arrange
+	runner := async.NewRunner()
+	async.Wait(runner.RunLimited(context.Background(), 2, 3, func(ctx context.Context) error { return nil }))
+
+	registry := NewRegistry()
+	registry.RegisterRunner("loader", runner)
+
+	recorder := httptest.NewRecorder()
+
+	// act
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	// assert
+	text := recorder.Body.String()
+	assert.Contains(t, text, `async_runner_tasks_started_total{runner="loader"} 6`+"\n")
+	assert.Contains(t, text, `async_runner_tasks_completed_total{runner="loader",result="succeeded"} 6`+"\n")
+	assert.Contains(t, text, `async_runner_task_duration_seconds_bucket{runner="loader",le="0.0001"}`)
+}
+
+func Test_Registry_Write_MultiplePools(t *testing.T) {
+	// arrange
+	registry := NewRegistry()
+	registry.RegisterPool("b", async.NewTaskPool(2))
+	registry.RegisterPool("a \"quoted\"", async.NewTaskPool(1))
+	registry.RegisterPool("c", async.NewTaskPool(3))
+	registry.UnregisterPool("c")
+
+	var b strings.Builder
+
+	// act
+	err := registry.Write(&b)
+
+	// assert
+	assert.NoError(t, err)
+
+	text := b.String()
+	first := strings.Index(text, `async_pool_capacity{pool="a \"quoted\""} 1`)
+	second := strings.Index(text, `async_pool_capacity{pool="b"} 2`)
+	assert.True(t, first >= 0)
+	assert.True(t, second > first)
+	assert.NotContains(t, text, `pool="c"`)
+}
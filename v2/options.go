@@ -0,0 +1,95 @@
+package async
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Option configures the behavior of a Runner or TaskPool.
+type Option func(*options)
+
+// options holds the configuration shared by runners and pools.
+type options struct {
+	recoverPanics bool
+	taskErrors    bool
+	timeout       time.Duration
+	timeoutPolicy TimeoutPolicy
+	leaked        *atomic.Int64
+	stats         *stats
+	limiter       *Limiter
+	breaker       *Breaker
+	aging         time.Duration
+	name          string
+	tracer        Tracer
+	log           *taskLogger
+}
+
+// newOptions returns the default options with the given options applied.
+func newOptions(opts []Option) options {
+	o := options{
+		recoverPanics: true,
+		leaked:        new(atomic.Int64),
+		stats:         new(stats),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithPanicRecovery sets whether a panic in a task is recovered and returned as a *PanicError. Recovery is enabled by default; disabling it lets a panicking task crash the program.
+func WithPanicRecovery(enabled bool) Option {
+	return func(o *options) {
+		o.recoverPanics = enabled
+	}
+}
+
+// WithTaskErrors sets whether an error returned by a task is wrapped in a *TaskError identifying the task that failed. It is disabled by default so that a task's own error is returned unchanged.
+func WithTaskErrors(enabled bool) Option {
+	return func(o *options) {
+		o.taskErrors = enabled
+	}
+}
+
+// WithTimeout limits how long a single task may run. The context given to the task is cancelled once the timeout expires and ErrTaskTimeout is returned. The policy determines what happens to a task that does not return after its context is cancelled.
+func WithTimeout(d time.Duration, policy TimeoutPolicy) Option {
+	return func(o *options) {
+		o.timeout = d
+		o.timeoutPolicy = policy
+	}
+}
+
+// WithRateLimit limits how often a Runner executes tasks, shared across all of its goroutines. The limiter can be adjusted while tasks are running. It has no effect on a TaskPool.
+func WithRateLimit(l *Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithBreaker rejects tasks with ErrCircuitOpen while the breaker is open and records the result of every other task with it. A TaskPool checks the breaker before waiting for capacity, so rejected tasks never take a slot.
+func WithBreaker(b *Breaker) Option {
+	return func(o *options) {
+		o.breaker = b
+	}
+}
+
+// WithAging raises the priority of a task waiting for capacity in a TaskPool by one for every interval it waits, so low priority tasks eventually run. It has no effect on a Runner.
+func WithAging(interval time.Duration) Option {
+	return func(o *options) {
+		o.aging = interval
+	}
+}
+
+// WithName names a Runner or TaskPool so that its tasks can be told apart in errors, tracing and logs. Individual tasks are named with WithTaskName.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithTracer calls the tracer before and after every task executed by a Runner or TaskPool.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
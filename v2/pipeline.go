@@ -0,0 +1,215 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// StageFunc processes a single item in a pipeline stage.
+type StageFunc func(ctx context.Context, item any) (any, error)
+
+// StageOf adapts a typed function to a StageFunc. The stage fails if it is given an item that is not an In.
+func StageOf[In any, Out any](fn func(ctx context.Context, item In) (Out, error)) StageFunc {
+	return func(ctx context.Context, item any) (any, error) {
+		in, ok := item.(In)
+		if !ok {
+			var zero In
+			return nil, &TypeError{Want: zero, Got: item}
+		}
+		return fn(ctx, in)
+	}
+}
+
+// TypeError is returned when a pipeline stage is given an item of the wrong type.
+type TypeError struct {
+	// Want is the zero value of the type the stage expected.
+	Want any
+
+	// Got is the item the stage was given.
+	Got any
+}
+
+// Error returns a description of the mismatch.
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("stage expected %T but got %T", e.Want, e.Got)
+}
+
+// StageStats is a snapshot of the activity of a pipeline stage.
+type StageStats struct {
+	// Name is the name of the stage.
+	Name string
+
+	// Workers is the number of goroutines processing items for the stage.
+	Workers int
+
+	// Busy is the number of workers currently processing an item.
+	Busy int64
+
+	// Backlog is the number of items waiting in the stage's input channel.
+	Backlog int
+
+	// Processed is the number of items the stage has finished processing.
+	Processed uint64
+
+	// Failed is the number of items the stage failed to process.
+	Failed uint64
+}
+
+// Pipeline is a chain of stages connected by bounded channels, where each stage processes items on its own number of goroutines and passes the results to the next stage.
+type Pipeline struct {
+	buffer  int
+	stages  []*stage
+	running atomic.Bool
+}
+
+// stage is a single step in a pipeline.
+type stage struct {
+	name      string
+	workers   int
+	fn        StageFunc
+	in        atomic.Pointer[<-chan any]
+	busy      atomic.Int64
+	processed atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// NewPipeline creates a new pipeline whose stages are connected by channels that hold up to buffer items.
+func NewPipeline(buffer int) *Pipeline {
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	return &Pipeline{
+		buffer: buffer,
+	}
+}
+
+// Stage adds a stage that processes items with fn on the given number of goroutines and returns the pipeline so calls can be chained.
+func (p *Pipeline) Stage(name string, workers int, fn StageFunc) *Pipeline {
+	if workers <= 0 {
+		panic("workers must be a value of >= 1")
+	}
+
+	p.stages = append(p.stages, &stage{
+		name:    name,
+		workers: workers,
+		fn:      fn,
+	})
+
+	return p
+}
+
+// Run passes every item from in through the stages and sends the results of the last stage on the returned channel. When in is closed, each stage finishes the items it has been given before the next stage is closed, so the pipeline drains in order. The first error from any stage, or the context's error if it is cancelled, cancels the whole pipeline and is sent on the error channel, which is closed once every stage has stopped. The results must be read until the channel is closed or the context cancelled. A pipeline can only be run once.
+func (p *Pipeline) Run(ctx context.Context, in <-chan any) (<-chan any, <-chan error) {
+	if !p.running.CompareAndSwap(false, true) {
+		panic("pipeline can only be run once")
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+
+	errc := make(chan error, 1)
+	var once sync.Once
+	fail := func(err error) {
+		once.Do(func() {
+			errc <- err
+			cancel()
+		})
+	}
+
+	var all sync.WaitGroup
+	for _, s := range p.stages {
+		source := in
+		s.in.Store(&source)
+		out := make(chan any, p.buffer)
+
+		var wg sync.WaitGroup
+		for w := 0; w < s.workers; w++ {
+			wg.Add(1)
+			all.Add(1)
+			go func(s *stage, in <-chan any, index int) {
+				defer all.Done()
+				defer wg.Done()
+				s.work(ctx, index, in, out, fail)
+			}(s, in, w)
+		}
+
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		in = out
+	}
+
+	go func() {
+		all.Wait()
+		if err := parent.Err(); err != nil {
+			fail(err)
+		}
+		cancel()
+		close(errc)
+	}()
+
+	return in, errc
+}
+
+// Stats returns a snapshot of the activity of every stage in order.
+func (p *Pipeline) Stats() []StageStats {
+	stats := make([]StageStats, len(p.stages))
+	for i, s := range p.stages {
+		stats[i] = StageStats{
+			Name:      s.name,
+			Workers:   s.workers,
+			Busy:      s.busy.Load(),
+			Processed: s.processed.Load(),
+			Failed:    s.failed.Load(),
+		}
+
+		if in := s.in.Load(); in != nil {
+			stats[i].Backlog = len(*in)
+		}
+	}
+
+	return stats
+}
+
+// work processes items from in and sends the results to out until in is closed or the context is cancelled.
+func (s *stage) work(ctx context.Context, index int, in <-chan any, out chan<- any, fail func(error)) {
+	for {
+		var item any
+		var ok bool
+		select {
+		case item, ok = <-in:
+			if !ok {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+
+		s.busy.Add(1)
+		var result any
+		err := defaultRunner.opts.call(ctx, TaskInfo{TaskName: s.name, Index: index}, func(ctx context.Context) error {
+			var err error
+			result, err = s.fn(ctx, item)
+			return err
+		})
+		s.busy.Add(-1)
+
+		if err != nil {
+			s.failed.Add(1)
+			fail(identify(TaskInfo{TaskName: s.name, Index: index}, err))
+			return
+		}
+		s.processed.Add(1)
+
+		select {
+		case out <- result:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
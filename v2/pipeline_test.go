@@ -0,0 +1,256 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// feedAny returns a channel that sends the given items and then closes.
+func feedAny(items ...any) <-chan any {
+	in := make(chan any)
+	go func() {
+		defer close(in)
+		for _, item := range items {
+			in <- item
+		}
+	}()
+	return in
+}
+
+func Test_Pipeline_Run_Success(t *testing.T) {
+	// arrange
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	p := NewPipeline(4).
+		Stage("double", 3, StageOf(func(ctx context.Context, item int) (int, error) {
+			return item * 2, nil
+		})).
+		Stage("format", 2, StageOf(func(ctx context.Context, item int) (string, error) {
+			return strconv.Itoa(item), nil
+		}))
+
+	// act
+	out, errc := p.Run(context.Background(), feedAny(items...))
+
+	sum := 0
+	for r := range out {
+		n, err := strconv.Atoi(r.(string))
+		assert.NoError(t, err)
+		sum += n
+	}
+
+	// assert
+	assert.NoError(t, WaitAll(errc))
+	assert.Equal(t, 9900, sum)
+
+	stats := p.Stats()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "double", stats[0].Name)
+	assert.Equal(t, 3, stats[0].Workers)
+	assert.Equal(t, uint64(100), stats[0].Processed)
+	assert.Equal(t, "format", stats[1].Name)
+	assert.Equal(t, uint64(100), stats[1].Processed)
+	assert.Equal(t, int64(0), stats[1].Busy)
+}
+
+func Test_Pipeline_Run_Error(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	boom := errors.New("boom")
+	stop := make(chan struct{})
+	in := make(chan any)
+	go func() {
+		defer close(in)
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	p := NewPipeline(1).
+		Stage("pass", 2, func(ctx context.Context, item any) (any, error) {
+			return item, nil
+		}).
+		Stage("fail", 2, func(ctx context.Context, item any) (any, error) {
+			if item.(int) == 10 {
+				return nil, boom
+			}
+			return item, nil
+		})
+
+	// act
+	out, errc := p.Run(context.Background(), in)
+	for range out {
+	}
+	err := WaitAll(errc)
+	close(stop)
+
+	// assert
+	assert.True(t, errors.Is(err, boom))
+	var taskErr *TaskError
+	assert.True(t, errors.As(err, &taskErr))
+	assert.Equal(t, "fail", taskErr.Task.TaskName)
+	assert.Equal(t, uint64(1), p.Stats()[1].Failed)
+	assertGoroutines(t, before)
+}
+
+func Test_Pipeline_Run_Panic(t *testing.T) {
+	// arrange
+	p := NewPipeline(0).
+		Stage("panic", 1, func(ctx context.Context, item any) (any, error) {
+			panic("oops")
+		})
+
+	// act
+	out, errc := p.Run(context.Background(), feedAny(1, 2, 3))
+	for range out {
+	}
+	err := Wait(errc)
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "oops", panicErr.Value)
+	assert.Equal(t, "panic", panicErr.Task.TaskName)
+}
+
+func Test_Pipeline_Run_TypeError(t *testing.T) {
+	// arrange
+	p := NewPipeline(0).
+		Stage("ints", 1, StageOf(func(ctx context.Context, item int) (int, error) {
+			return item, nil
+		}))
+
+	// act
+	out, errc := p.Run(context.Background(), feedAny("not an int"))
+	for range out {
+	}
+	err := Wait(errc)
+
+	// assert
+	var typeErr *TypeError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "stage expected int but got string", typeErr.Error())
+}
+
+func Test_Pipeline_Run_Cancel(t *testing.T) {
+	// arrange
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan any)
+	p := NewPipeline(0).
+		Stage("block", 2, func(ctx context.Context, item any) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	// act
+	out, errc := p.Run(ctx, in)
+	in <- 1
+	cancel()
+	for range out {
+	}
+	err := WaitAll(errc)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+	assertGoroutines(t, before)
+}
+
+func Test_Pipeline_Run_DrainInOrder(t *testing.T) {
+	// arrange
+	var firstDone atomic.Bool
+	var outOfOrder atomic.Bool
+
+	p := NewPipeline(8).
+		Stage("first", 2, func(ctx context.Context, item any) (any, error) {
+			time.Sleep(time.Millisecond)
+			return item, nil
+		}).
+		Stage("second", 2, func(ctx context.Context, item any) (any, error) {
+			if firstDone.Load() {
+				outOfOrder.Store(true)
+			}
+			return item, nil
+		})
+
+	// act
+	out, errc := p.Run(context.Background(), feedAny(1, 2, 3, 4, 5, 6, 7, 8))
+	count := 0
+	for range out {
+		count++
+	}
+
+	// assert
+	assert.NoError(t, Wait(errc))
+	assert.Equal(t, 8, count)
+	assert.False(t, outOfOrder.Load())
+	assert.Equal(t, uint64(8), p.Stats()[0].Processed)
+}
+
+func Test_Pipeline_Stats_Backlog(t *testing.T) {
+	// arrange
+	release := make(chan struct{})
+	p := NewPipeline(4).
+		Stage("fast", 1, func(ctx context.Context, item any) (any, error) {
+			return item, nil
+		}).
+		Stage("slow", 1, func(ctx context.Context, item any) (any, error) {
+			<-release
+			return item, nil
+		})
+
+	// act
+	out, errc := p.Run(context.Background(), feedAny(1, 2, 3, 4, 5, 6))
+
+	// assert
+	assertEventually(t, func() bool {
+		stats := p.Stats()
+		return stats[1].Busy == 1 && stats[1].Backlog == 4
+	})
+
+	close(release)
+	for range out {
+	}
+	assert.NoError(t, Wait(errc))
+	assert.Equal(t, 0, p.Stats()[1].Backlog)
+}
+
+func Test_Pipeline_Run_Twice(t *testing.T) {
+	// arrange
+	p := NewPipeline(0).
+		Stage("pass", 1, func(ctx context.Context, item any) (any, error) {
+			return item, nil
+		})
+	out, errc := p.Run(context.Background(), feedAny())
+	for range out {
+	}
+	assert.NoError(t, Wait(errc))
+
+	// assert
+	assert.Panics(t, func() {
+		p.Run(context.Background(), feedAny())
+	})
+}
+
+func Test_Pipeline_Stage_InvalidWorkers(t *testing.T) {
+	// assert
+	assert.Panics(t, func() {
+		NewPipeline(0).Stage("bad", 0, nil)
+	})
+}
@@ -2,64 +2,246 @@ package async
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrWeightExceedsMax is returned when a task needs more capacity than the pool has.
+	ErrWeightExceedsMax = errors.New("task weight exceeds pool max")
 
-	"golang.org/x/sync/semaphore"
+	// ErrPoolClosed is returned when a task is run on a pool that has been closed.
+	ErrPoolClosed = errors.New("task pool is closed")
+
+	// ErrPoolSaturated is returned when a task is rejected because the pool has no available capacity.
+	ErrPoolSaturated = errors.New("task pool is saturated")
 )
 
 // TaskPool limits the number of concurrent tasks being processed to a given max.
 type TaskPool struct {
-	max int
-	sem *semaphore.Weighted
+	mu      sync.Mutex
+	max     int
+	sem     *semaphore
+	opts    options
+	nextID  atomic.Uint64
+	running map[uint64]context.CancelFunc
 }
 
 // NewTaskPool creates a new task pool that will limit concurrent tasks to max.
-func NewTaskPool(max int) *TaskPool {
+func NewTaskPool(max int, opts ...Option) *TaskPool {
 	if max <= 0 {
 		panic("max must be a value of >= 1")
 	}
 
+	o := newOptions(opts)
+	sem := newSemaphore(int64(max))
+	sem.aging = o.aging
+
 	return &TaskPool{
-		max: max,
-		sem: semaphore.NewWeighted(int64(max)),
+		max:     max,
+		sem:     sem,
+		opts:    o,
+		running: make(map[uint64]context.CancelFunc),
 	}
 }
 
 // Run will block until there is available capacity and then execute the given task. Cancelling the context will stop the task from being started.
 func (p *TaskPool) Run(ctx context.Context, task Task) <-chan error {
+	return p.RunContext(ctx, withContext(task))
+}
+
+// RunContext will block until there is available capacity and then execute the given task. Cancelling the context will stop the task from being started and interrupt it while it is in progress.
+func (p *TaskPool) RunContext(ctx context.Context, task TaskContext) <-chan error {
+	return p.RunWeighted(ctx, 1, task)
+}
+
+// RunWeighted will block until weight capacity is available and then execute the given task, so a heavy task can count as several of the pool's max concurrent tasks. Returns ErrWeightExceedsMax if weight is larger than the pool's max, or ErrPoolClosed if the pool has been closed. Cancelling the context will stop the task from being started and interrupt it while it is in progress.
+func (p *TaskPool) RunWeighted(ctx context.Context, weight int, task TaskContext) <-chan error {
+	if weight <= 0 {
+		panic("weight must be a value of >= 1")
+	}
+
+	return p.start(ctx, weight, task, func(n int64) error {
+		return p.sem.Acquire(ctx, n)
+	})
+}
+
+// RunPriority will block until there is available capacity and then execute the given task. Waiting tasks with a higher priority are started before those with a lower priority, and tasks run with Run have a priority of 0. Cancelling the context will stop the task from being started and interrupt it while it is in progress.
+func (p *TaskPool) RunPriority(ctx context.Context, priority int, task TaskContext) <-chan error {
+	return p.start(ctx, 1, task, func(n int64) error {
+		return p.sem.AcquirePriority(ctx, n, priority)
+	})
+}
+
+// TryRun will execute the given task only if there is available capacity right away, returning true if the task was started. Otherwise, the returned channel receives ErrPoolSaturated, or ErrPoolClosed if the pool has been closed.
+func (p *TaskPool) TryRun(task Task) (<-chan error, bool) {
+	started := false
+	errc := p.start(context.Background(), 1, withContext(task), func(n int64) error {
+		err := p.sem.TryAcquire(n)
+		started = err == nil
+		return err
+	})
+
+	return errc, started
+}
+
+// RunTimeout will block until there is available capacity and then execute the given task, waiting at most d. Returns ErrPoolSaturated if capacity did not become available in time.
+func (p *TaskPool) RunTimeout(d time.Duration, task Task) <-chan error {
+	return p.start(context.Background(), 1, withContext(task), func(n int64) error {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		defer cancel()
+
+		err := p.sem.Acquire(ctx, n)
+		if err == context.DeadlineExceeded {
+			return ErrPoolSaturated
+		}
+		return err
+	})
+}
+
+// start acquires capacity for the task using acquire and then executes the task with a context derived from ctx.
+func (p *TaskPool) start(ctx context.Context, weight int, task TaskContext, acquire func(n int64) error) <-chan error {
 	errc := make(chan error, 1)
 
-	err := p.sem.Acquire(ctx, 1)
+	// reject tasks for a closed pool before the breaker so they don't count against it
+	if p.sem.Closed() {
+		errc <- ErrPoolClosed
+		close(errc)
+		return errc
+	}
+
+	done, err := p.opts.admit()
+	if err != nil {
+		errc <- err
+		close(errc)
+		return errc
+	}
+
+	// number tasks in the order they are submitted so errors, traces and logs can tell them apart
+	id := p.nextID.Add(1) - 1
+	info := TaskInfo{Name: p.opts.name, TaskName: TaskName(ctx), Index: int(id)}
+	ctx = p.opts.logQueued(ctx, info)
+
+	start := time.Now()
+	p.opts.stats.waiting.Add(1)
+	err = acquire(int64(weight))
+	p.opts.stats.waiting.Add(-1)
+	wait := time.Since(start)
+	p.opts.stats.acquireWait.observe(wait)
 	if err != nil {
+		p.opts.logResult(ctx, info, 0, err)
+		done(errAborted)
 		errc <- err
 		close(errc)
 		return errc
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	p.track(id, cancel)
 	go func() {
-		defer p.sem.Release(1)
+		defer p.sem.Release(int64(weight))
 		defer close(errc)
+		defer p.untrack(id)
+		defer cancel()
 
-		err = task()
+		err = p.opts.execute(ctx, info, wait, task)
+		done(err)
 		if err != nil {
-			errc <- err
+			errc <- p.opts.identify(info, err)
 		}
 	}()
 
 	return errc
 }
 
+// Leaked returns the number of tasks abandoned after timing out that are still running.
+func (p *TaskPool) Leaked() int {
+	return int(p.opts.leaked.Load())
+}
+
+// Stats returns a snapshot of the activity of the pool.
+func (p *TaskPool) Stats() PoolStats {
+	s := p.opts.stats
+	return PoolStats{
+		InFlight:    s.inFlight.Load(),
+		Waiting:     s.waiting.Load(),
+		Leaked:      p.opts.leaked.Load(),
+		Started:     s.started.Load(),
+		Succeeded:   s.succeeded.Load(),
+		Failed:      s.failed.Load(),
+		Panicked:    s.panicked.Load(),
+		Duration:    s.duration.snapshot(),
+		AcquireWait: s.acquireWait.snapshot(),
+	}
+}
+
+// Resize changes the max number of concurrent tasks. Growing the pool lets waiting tasks start immediately. Shrinking the pool does not interrupt running tasks; new tasks wait until enough running tasks have finished.
+func (p *TaskPool) Resize(max int) {
+	if max <= 0 {
+		panic("max must be a value of >= 1")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.max = max
+	p.sem.Resize(int64(max))
+}
+
+// Max returns the max number of concurrent tasks.
+func (p *TaskPool) Max() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.max
+}
+
 // Wait until all tasks have finished processing.
 func (p *TaskPool) Wait() error {
-	// acquire all available slots in semaphore
-	for i := 0; i < p.max; i++ {
-		err := p.sem.Acquire(context.Background(), 1)
-		if err != nil {
-			return err
+	return p.WaitContext(context.Background())
+}
+
+// WaitContext will block until all tasks have finished processing or the context is cancelled.
+func (p *TaskPool) WaitContext(ctx context.Context) error {
+	return p.sem.Wait(ctx)
+}
+
+// Close stops the pool from accepting tasks. Tasks waiting for capacity and any tasks run later return ErrPoolClosed, while running tasks are left to finish.
+func (p *TaskPool) Close() {
+	p.sem.Close()
+}
+
+// Shutdown closes the pool and waits for running tasks to finish. If the context is cancelled first, the contexts of the running tasks are cancelled and the context's error is returned.
+func (p *TaskPool) Shutdown(ctx context.Context) error {
+	p.Close()
+
+	err := p.WaitContext(ctx)
+	if err != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		for _, cancel := range p.running {
+			cancel()
 		}
 	}
 
-	// all tasks have completed; release the semaphore
-	p.sem.Release(int64(p.max))
+	return err
+}
+
+// track records the cancel function of a running task so it can be cancelled by Shutdown.
+func (p *TaskPool) track(id uint64, cancel context.CancelFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running[id] = cancel
+}
+
+// untrack removes a task that has finished running.
+func (p *TaskPool) untrack(id uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	return nil
+	delete(p.running, id)
 }
@@ -245,3 +245,445 @@ func Test_TaskPool_Wait_Success(t *testing.T) {
 	assert.True(t, startedTask2)
 	assert.True(t, finishedTask2)
 }
+
+func Test_TaskPool_RunContext_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan bool)
+	task := func(ctx context.Context) error {
+		started <- true
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	pool := NewTaskPool(1)
+
+	// act
+	errc := pool.RunContext(ctx, task)
+	<-started
+	cancel()
+
+	// assert
+	err := <-errc
+	assert.Equal(t, context.Canceled, err)
+	assert.NoError(t, pool.Wait())
+}
+
+func Test_TaskPool_Run_Panic(t *testing.T) {
+	// arrange
+	task := func() error {
+		panic("task panic")
+	}
+
+	pool := NewTaskPool(1)
+
+	// act
+	errc := pool.Run(context.Background(), task)
+
+	// assert
+	err := <-errc
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "task panic", panicErr.Value)
+	assert.NoError(t, pool.Wait())
+}
+
+func Test_TaskPool_WithTaskErrors(t *testing.T) {
+	// arrange
+	errTask := errors.New("task error")
+	task := func() error {
+		return errTask
+	}
+
+	plain := NewTaskPool(1)
+	named := NewTaskPool(1, WithName("workers"), WithTaskErrors(true))
+
+	// act
+	err1 := <-plain.Run(context.Background(), task)
+	err2 := <-named.Run(context.Background(), task)
+	err3 := <-named.Run(context.Background(), task)
+
+	// assert
+	assert.Equal(t, errTask, err1)
+	assert.True(t, errors.Is(err2, errTask))
+	var taskErr *TaskError
+	assert.True(t, errors.As(err3, &taskErr))
+	assert.Equal(t, 1, taskErr.Task.Index)
+	assert.EqualError(t, err3, "workers task 1: task error")
+}
+
+func Test_TaskPool_Run_PanicIndex(t *testing.T) {
+	// arrange
+	task := func() error {
+		panic("task panic")
+	}
+
+	pool := NewTaskPool(2, WithName("workers"))
+
+	// act
+	err1 := <-pool.Run(context.Background(), task)
+	err2 := <-pool.Run(context.Background(), task)
+
+	// assert
+	var panicErr1, panicErr2 *PanicError
+	assert.True(t, errors.As(err1, &panicErr1))
+	assert.True(t, errors.As(err2, &panicErr2))
+	assert.Equal(t, TaskInfo{Name: "workers", Index: 0}, panicErr1.Task)
+	assert.Equal(t, TaskInfo{Name: "workers", Index: 1}, panicErr2.Task)
+	assert.Equal(t, "workers task 1 panicked: task panic", err2.Error())
+}
+
+func Test_TaskPool_Resize_Grow(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	task := func() error {
+		<-release
+		return nil
+	}
+	pool.Run(context.Background(), task)
+
+	started := make(chan bool)
+	go pool.Run(context.Background(), func() error {
+		close(started)
+		return nil
+	})
+
+	// act
+	pool.Resize(2)
+
+	// assert
+	<-started
+	assert.Equal(t, 2, pool.Max())
+	close(release)
+	assert.NoError(t, pool.Wait())
+}
+
+func Test_TaskPool_Resize_Shrink(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	release := make(chan bool)
+	task := func() error {
+		<-release
+		return nil
+	}
+	pool.Run(context.Background(), task)
+	pool.Run(context.Background(), task)
+
+	// act
+	pool.Resize(1)
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	err := <-pool.Run(ctx, task)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	close(release)
+	assert.NoError(t, pool.Wait())
+	assert.NoError(t, <-pool.Run(context.Background(), func() error { return nil }))
+}
+
+func Test_TaskPool_Resize_Invalid(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	// assert
+	assert.Panics(t, func() { pool.Resize(0) })
+	assert.Equal(t, 1, pool.Max())
+}
+
+func Test_TaskPool_RunWeighted_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(3)
+
+	release := make(chan bool)
+	heavy := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+
+	// act
+	errc := pool.RunWeighted(context.Background(), 2, heavy)
+
+	// assert
+	assert.NoError(t, <-pool.RunContext(context.Background(), func(ctx context.Context) error { return nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, <-pool.RunWeighted(ctx, 2, heavy))
+
+	close(release)
+	assert.NoError(t, <-errc)
+}
+
+func Test_TaskPool_RunWeighted_ExceedsMax(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	// act
+	errc := pool.RunWeighted(context.Background(), 3, func(ctx context.Context) error {
+		return nil
+	})
+
+	// assert
+	assert.Equal(t, ErrWeightExceedsMax, <-errc)
+}
+
+func Test_TaskPool_RunWeighted_ExceedsMaxAfterResize(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(3)
+
+	release := make(chan bool)
+	pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	errc := make(chan (<-chan error))
+	go func() {
+		errc <- pool.RunWeighted(context.Background(), 3, func(ctx context.Context) error {
+			return nil
+		})
+	}()
+	assertEventually(t, func() bool { return waiters(pool.sem) == 1 })
+
+	// act
+	pool.Resize(2)
+
+	// assert
+	assert.Equal(t, ErrWeightExceedsMax, <-<-errc)
+	close(release)
+}
+
+func Test_TaskPool_RunWeighted_Invalid(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	// assert
+	assert.Panics(t, func() {
+		pool.RunWeighted(context.Background(), 0, func(ctx context.Context) error { return nil })
+	})
+}
+
+func Test_TaskPool_Close_RejectsTasks(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	errc1 := pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	waiting := make(chan (<-chan error))
+	go func() {
+		waiting <- pool.Run(context.Background(), func() error { return nil })
+	}()
+	assertEventually(t, func() bool { return waiters(pool.sem) == 1 })
+
+	// act
+	pool.Close()
+
+	// assert
+	assert.Equal(t, ErrPoolClosed, <-<-waiting)
+	assert.Equal(t, ErrPoolClosed, <-pool.Run(context.Background(), func() error { return nil }))
+
+	close(release)
+	assert.NoError(t, <-errc1)
+	assert.NoError(t, pool.Wait())
+}
+
+func Test_TaskPool_WaitContext_Timeout(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	defer close(release)
+	pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// act
+	err := pool.WaitContext(ctx)
+
+	// assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func Test_TaskPool_Shutdown_Drains(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	finished := make(chan bool, 1)
+	pool.Run(context.Background(), func() error {
+		time.Sleep(time.Millisecond * 50)
+		finished <- true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// act
+	err := pool.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, finished, 1)
+	assert.Equal(t, ErrPoolClosed, <-pool.Run(context.Background(), func() error { return nil }))
+}
+
+func Test_TaskPool_Shutdown_CancelsAfterDeadline(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(2)
+
+	errc := pool.RunContext(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// act
+	err := pool.Shutdown(ctx)
+
+	// assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, context.Canceled, <-errc)
+	assert.NoError(t, pool.Wait())
+}
+
+func Test_TaskPool_TryRun_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	// act
+	errc, ok := pool.TryRun(func() error {
+		return nil
+	})
+
+	// assert
+	assert.True(t, ok)
+	assert.NoError(t, <-errc)
+}
+
+func Test_TaskPool_TryRun_Saturated(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	defer close(release)
+	pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	started := false
+	task := func() error {
+		started = true
+		return nil
+	}
+
+	// act
+	errc, ok := pool.TryRun(task)
+
+	// assert
+	assert.False(t, ok)
+	assert.Equal(t, ErrPoolSaturated, <-errc)
+	assert.False(t, started)
+}
+
+func Test_TaskPool_TryRun_Closed(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+	pool.Close()
+
+	// act
+	errc, ok := pool.TryRun(func() error {
+		return nil
+	})
+
+	// assert
+	assert.False(t, ok)
+	assert.Equal(t, ErrPoolClosed, <-errc)
+}
+
+func Test_TaskPool_RunTimeout_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	pool.Run(context.Background(), func() error {
+		time.Sleep(time.Millisecond * 20)
+		return nil
+	})
+
+	// act
+	errc := pool.RunTimeout(time.Second, func() error {
+		return nil
+	})
+
+	// assert
+	assert.NoError(t, <-errc)
+}
+
+func Test_TaskPool_RunTimeout_Saturated(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	defer close(release)
+	pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	// act
+	start := time.Now()
+	errc := pool.RunTimeout(time.Millisecond*50, func() error {
+		return nil
+	})
+
+	// assert
+	assert.Equal(t, ErrPoolSaturated, <-errc)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Millisecond*50))
+}
+
+func Test_TaskPool_RunPriority_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1, WithAging(time.Hour))
+
+	release := make(chan bool)
+	pool.Run(context.Background(), func() error {
+		<-release
+		return nil
+	})
+
+	order := make(chan string, 2)
+	go pool.RunPriority(context.Background(), 0, func(ctx context.Context) error {
+		order <- "batch"
+		return nil
+	})
+	assertEventually(t, func() bool { return waiters(pool.sem) == 1 })
+
+	go pool.RunPriority(context.Background(), 10, func(ctx context.Context) error {
+		order <- "interactive"
+		return nil
+	})
+	assertEventually(t, func() bool { return waiters(pool.sem) == 2 })
+
+	// act
+	close(release)
+
+	// assert
+	assert.Equal(t, "interactive", <-order)
+	assert.Equal(t, "batch", <-order)
+}
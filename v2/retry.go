@@ -0,0 +1,146 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns how long to wait before the given retry, where attempt is 1 for the first retry and prev is the previous wait.
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// RetryPolicy configures how a task is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times the task will be executed. Zero or less will retry until the task succeeds.
+	MaxAttempts int
+
+	// MaxElapsed stops retrying once waiting for the next attempt would exceed this much time since the first attempt. Zero places no limit.
+	MaxElapsed time.Duration
+
+	// Backoff returns how long to wait between attempts. Nil will retry immediately.
+	Backoff Backoff
+
+	// Retryable reports whether an error should be retried. Nil will retry every error.
+	Retryable func(err error) bool
+}
+
+// RetryError is returned when a task fails after being retried.
+type RetryError struct {
+	// Attempts is the number of times the task was executed.
+	Attempts int
+
+	// Err is the error returned by the last attempt.
+	Err error
+}
+
+// Error returns a description of the failure.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error returned by the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry wraps a task so that it is executed again according to the policy when it fails. Waits between attempts end early if the context is cancelled.
+func Retry(task TaskContext, policy RetryPolicy) TaskContext {
+	return func(ctx context.Context) error {
+		start := time.Now()
+
+		var wait time.Duration
+		for attempt := 1; ; attempt++ {
+			err := task(ctx)
+			if err == nil {
+				return nil
+			}
+
+			if policy.Retryable != nil && !policy.Retryable(err) {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+
+			if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+
+			if policy.Backoff != nil {
+				wait = policy.Backoff(attempt, wait)
+			}
+
+			if policy.MaxElapsed > 0 && time.Since(start)+wait > policy.MaxElapsed {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+
+			if serr := sleep(ctx, wait); serr != nil {
+				return &RetryError{Attempts: attempt, Err: errors.Join(err, serr)}
+			}
+		}
+	}
+}
+
+// sleep waits for the given duration or until the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ConstantBackoff waits the same amount of time before every retry.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(attempt int, prev time.Duration) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the wait before every retry, starting at base and never exceeding max.
+func ExponentialBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(attempt int, prev time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return capDuration(d, max)
+	}
+}
+
+// FibonacciBackoff waits base multiplied by the next fibonacci number before every retry, never exceeding max.
+func FibonacciBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(attempt int, prev time.Duration) time.Duration {
+		a, b := base, base
+		for i := 1; i < attempt && a < max; i++ {
+			a, b = b, a+b
+		}
+		return capDuration(a, max)
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random amount of time between base and three times the previous wait before every retry, never exceeding max.
+func DecorrelatedJitterBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(attempt int, prev time.Duration) time.Duration {
+		if prev < base {
+			prev = base
+		}
+		d := base + time.Duration(rand.Int63n(int64(prev*3-base)+1))
+		return capDuration(d, max)
+	}
+}
+
+// capDuration limits d to max.
+func capDuration(d time.Duration, max time.Duration) time.Duration {
+	if d > max {
+		return max
+	}
+	return d
+}
@@ -0,0 +1,163 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_Retry_Success(t *testing.T) {
+	// arrange
+	count := 0
+	task := func(ctx context.Context) error {
+		count++
+		if count < 3 {
+			return errors.New("task error")
+		}
+		return nil
+	}
+
+	// act
+	err := Retry(task, RetryPolicy{MaxAttempts: 5})(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func Test_Retry_MaxAttempts(t *testing.T) {
+	// arrange
+	errTask := errors.New("task error")
+	count := 0
+	task := func(ctx context.Context) error {
+		count++
+		return errTask
+	}
+
+	// act
+	err := Retry(task, RetryPolicy{MaxAttempts: 4})(context.Background())
+
+	// assert
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 4, retryErr.Attempts)
+	assert.True(t, errors.Is(err, errTask))
+	assert.Equal(t, 4, count)
+}
+
+func Test_Retry_Permanent(t *testing.T) {
+	// arrange
+	errPermanent := errors.New("permanent error")
+	count := 0
+	task := func(ctx context.Context) error {
+		count++
+		if count == 2 {
+			return errPermanent
+		}
+		return errors.New("task error")
+	}
+
+	policy := RetryPolicy{
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		},
+	}
+
+	// act
+	err := Retry(task, policy)(context.Background())
+
+	// assert
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 2, retryErr.Attempts)
+	assert.True(t, errors.Is(err, errPermanent))
+}
+
+func Test_Retry_MaxElapsed(t *testing.T) {
+	// arrange
+	task := func(ctx context.Context) error {
+		return errors.New("task error")
+	}
+
+	policy := RetryPolicy{
+		MaxElapsed: time.Millisecond * 100,
+		Backoff:    ConstantBackoff(time.Millisecond * 40),
+	}
+
+	// act
+	start := time.Now()
+	err := Retry(task, policy)(context.Background())
+
+	// assert
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.Less(t, int64(time.Since(start)), int64(time.Millisecond*100))
+}
+
+func Test_Retry_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	taskErr := errors.New("task error")
+	task := func(ctx context.Context) error {
+		return taskErr
+	}
+
+	policy := RetryPolicy{
+		Backoff: ConstantBackoff(time.Second * 5),
+	}
+
+	// act
+	err := Retry(task, policy)(ctx)
+
+	// assert
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 1, retryErr.Attempts)
+	assert.True(t, errors.Is(err, taskErr))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_ExponentialBackoff_Success(t *testing.T) {
+	// arrange
+	backoff := ExponentialBackoff(time.Millisecond*10, time.Millisecond*50)
+
+	// assert
+	assert.Equal(t, time.Millisecond*10, backoff(1, 0))
+	assert.Equal(t, time.Millisecond*20, backoff(2, 0))
+	assert.Equal(t, time.Millisecond*40, backoff(3, 0))
+	assert.Equal(t, time.Millisecond*50, backoff(4, 0))
+	assert.Equal(t, time.Millisecond*50, backoff(100, 0))
+}
+
+func Test_FibonacciBackoff_Success(t *testing.T) {
+	// arrange
+	backoff := FibonacciBackoff(time.Millisecond*10, time.Millisecond*100)
+
+	// assert
+	assert.Equal(t, time.Millisecond*10, backoff(1, 0))
+	assert.Equal(t, time.Millisecond*10, backoff(2, 0))
+	assert.Equal(t, time.Millisecond*20, backoff(3, 0))
+	assert.Equal(t, time.Millisecond*30, backoff(4, 0))
+	assert.Equal(t, time.Millisecond*50, backoff(5, 0))
+	assert.Equal(t, time.Millisecond*100, backoff(100, 0))
+}
+
+func Test_DecorrelatedJitterBackoff_Success(t *testing.T) {
+	// arrange
+	backoff := DecorrelatedJitterBackoff(time.Millisecond*10, time.Millisecond*100)
+
+	// assert
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 20; attempt++ {
+		d := backoff(attempt, prev)
+		assert.GreaterOrEqual(t, int64(d), int64(time.Millisecond*10))
+		assert.LessOrEqual(t, int64(d), int64(time.Millisecond*100))
+		prev = d
+	}
+}
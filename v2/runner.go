@@ -0,0 +1,271 @@
+package async
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// channels maps error channels returned by runners to their *errChan so they can be discarded.
+var channels sync.Map
+
+// defaultRunner is used by the package level run functions.
+var defaultRunner = NewRunner()
+
+// Runner executes tasks concurrently using a set of options.
+type Runner struct {
+	opts options
+}
+
+// NewRunner creates a new runner configured with the given options.
+func NewRunner(opts ...Option) *Runner {
+	return &Runner{
+		opts: newOptions(opts),
+	}
+}
+
+// Run will execute the given tasks concurrently and return any errors. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress.
+func (r *Runner) Run(ctx context.Context, tasks ...TaskContext) <-chan error {
+	ctx, cancel := context.WithCancel(ctx)
+	errc := newErrChan(cancel)
+
+	// run tasks
+	var wg sync.WaitGroup
+	for i, v := range tasks {
+		wg.Add(1)
+		go func(info TaskInfo, task TaskContext) {
+			defer wg.Done()
+			ctx := r.opts.logQueued(ctx, info)
+			wait, err := r.opts.throttle(ctx)
+			if err != nil {
+				errc.send(err)
+				return
+			}
+
+			err = r.opts.executeTask(ctx, info, wait, task)
+			if err != nil {
+				errc.send(err)
+			}
+		}(TaskInfo{Name: r.opts.name, TaskName: TaskName(ctx), Index: i}, v)
+	}
+
+	// make sure to close error channel
+	go func() {
+		wg.Wait()
+		errc.close()
+	}()
+
+	return errc.c
+}
+
+// RunForever will execute the given task repeatedly on a set number of goroutines and return any errors. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress as well as stop additional tasks from being started.
+func (r *Runner) RunForever(ctx context.Context, concurrent int, task TaskContext) <-chan error {
+	return r.loop(ctx, concurrent, -1, task)
+}
+
+// RunLimited will execute the given task a set number of times on a set number of goroutines and return any errors. Total times the task will be executed is equal to concurrent multiplied by count. Each task is given a context derived from ctx, so cancelling ctx will interrupt tasks that are in progress as well as stop additional tasks from being started.
+func (r *Runner) RunLimited(ctx context.Context, concurrent int, count int, task TaskContext) <-chan error {
+	return r.loop(ctx, concurrent, count, task)
+}
+
+// Leaked returns the number of tasks abandoned after timing out that are still running.
+func (r *Runner) Leaked() int {
+	return int(r.opts.leaked.Load())
+}
+
+// Stats returns a snapshot of the activity of the runner.
+func (r *Runner) Stats() RunnerStats {
+	s := r.opts.stats
+	return RunnerStats{
+		InFlight:  s.inFlight.Load(),
+		Leaked:    r.opts.leaked.Load(),
+		Started:   s.started.Load(),
+		Succeeded: s.succeeded.Load(),
+		Failed:    s.failed.Load(),
+		Panicked:  s.panicked.Load(),
+		Duration:  s.duration.snapshot(),
+	}
+}
+
+// loop executes the task count times on each of the concurrent goroutines, or until cancelled if count is negative.
+func (r *Runner) loop(ctx context.Context, concurrent int, count int, task TaskContext) <-chan error {
+	ctx, cancel := context.WithCancel(ctx)
+	errc := newErrChan(cancel)
+
+	// run tasks
+	var wg sync.WaitGroup
+	for c := 0; c < concurrent; c++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			for i := 0; count < 0 || i < count; i++ {
+				info := TaskInfo{Name: r.opts.name, TaskName: TaskName(ctx), Index: index, Iteration: i}
+				tctx := r.opts.logQueued(ctx, info)
+				wait, err := r.opts.throttle(tctx)
+				if err != nil {
+					errc.send(err)
+					return
+				}
+
+				err = r.opts.executeTask(tctx, info, wait, task)
+				if err != nil {
+					errc.send(err)
+				}
+
+				select {
+				case <-ctx.Done():
+					errc.send(ctx.Err())
+					return
+				default:
+				}
+			}
+		}(c)
+	}
+
+	// make sure to close error channel
+	go func() {
+		wg.Wait()
+		errc.close()
+	}()
+
+	return errc.c
+}
+
+// errChan is an error channel returned by a runner whose consumer may stop reading at any time.
+type errChan struct {
+	c      chan error
+	done   chan struct{}
+	once   sync.Once
+	cancel context.CancelFunc
+}
+
+// newErrChan creates an error channel for a run that is cancelled by cancel.
+func newErrChan(cancel context.CancelFunc) *errChan {
+	e := &errChan{
+		c:      make(chan error),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	channels.Store((<-chan error)(e.c), e)
+	return e
+}
+
+// send delivers an error unless the channel has been discarded.
+func (e *errChan) send(err error) {
+	select {
+	case e.c <- err:
+	case <-e.done:
+	}
+}
+
+// close cancels the run and closes the channel once all goroutines sending on it have finished.
+func (e *errChan) close() {
+	channels.Delete((<-chan error)(e.c))
+	e.cancel()
+	close(e.c)
+}
+
+// discard stops delivery of errors and cancels the run.
+func (e *errChan) discard() {
+	e.once.Do(func() {
+		close(e.done)
+		e.cancel()
+	})
+}
+
+// throttle blocks until the rate limiter allows another task to be executed and returns how long it waited.
+func (o *options) throttle(ctx context.Context) (time.Duration, error) {
+	if o.limiter == nil {
+		return 0, nil
+	}
+
+	start := time.Now()
+	err := o.limiter.Wait(ctx)
+	return time.Since(start), err
+}
+
+// executeTask runs a single task and identifies the task in any error it returns if task errors are enabled.
+func (o *options) executeTask(ctx context.Context, info TaskInfo, wait time.Duration, task TaskContext) error {
+	done, err := o.admit()
+	if err != nil {
+		return o.identify(info, err)
+	}
+
+	err = o.execute(ctx, info, wait, task)
+	done(err)
+	return o.identify(info, err)
+}
+
+// admit checks the breaker, if any, before a task is executed. The returned function records the result of the task.
+func (o *options) admit() (func(err error), error) {
+	if o.breaker == nil {
+		return func(error) {}, nil
+	}
+
+	return o.breaker.allow()
+}
+
+// identify wraps an error returned by a task in a *TaskError if task errors are enabled.
+func (o *options) identify(info TaskInfo, err error) error {
+	if !o.taskErrors {
+		return err
+	}
+	return identify(info, err)
+}
+
+// identify wraps an error returned by a task in a *TaskError, unless it already identifies the task.
+func identify(info TaskInfo, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*PanicError); ok {
+		return err
+	}
+
+	return &TaskError{
+		Task: info,
+		Err:  err,
+	}
+}
+
+// execute runs a single task that waited in a queue for the given duration, enforcing the timeout if one is set.
+func (o *options) execute(ctx context.Context, info TaskInfo, wait time.Duration, task TaskContext) (err error) {
+	if o.tracer != nil {
+		ctx = o.tracer.TaskStart(ctx, TaskEvent{Task: info, Wait: wait})
+		defer func() { o.tracer.TaskEnd(ctx, TaskEvent{Task: info, Wait: wait}, err) }()
+	}
+
+	start := time.Now()
+	o.stats.started.Add(1)
+	o.stats.inFlight.Add(1)
+	o.logStarted(ctx, info, wait)
+	defer func() {
+		o.stats.finish(start, err)
+		o.logResult(ctx, info, time.Since(start), err)
+	}()
+
+	if o.timeout > 0 {
+		return o.executeTimeout(ctx, info, task)
+	}
+
+	return o.call(ctx, info, task)
+}
+
+// call runs a single task, recovering from a panic if enabled.
+func (o *options) call(ctx context.Context, info TaskInfo, task TaskContext) (err error) {
+	if o.recoverPanics {
+		defer func() {
+			if v := recover(); v != nil {
+				err = &PanicError{
+					Task:  info,
+					Value: v,
+					Stack: debug.Stack(),
+				}
+			}
+		}()
+	}
+
+	return task(ctx)
+}
@@ -0,0 +1,109 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_NewRunner_Success(t *testing.T) {
+	// act
+	runner := NewRunner()
+
+	// assert
+	assert.NotNil(t, runner)
+	assert.True(t, runner.opts.recoverPanics)
+}
+
+func Test_NewRunner_WithPanicRecovery_Disabled(t *testing.T) {
+	// act
+	runner := NewRunner(WithPanicRecovery(false))
+
+	// assert
+	assert.False(t, runner.opts.recoverPanics)
+}
+
+func Test_Runner_Run_Panic(t *testing.T) {
+	// arrange
+	task1 := func(ctx context.Context) error {
+		return nil
+	}
+
+	task2 := func(ctx context.Context) error {
+		panic("task2 panic")
+	}
+
+	// act
+	errc := NewRunner().Run(context.Background(), task1, task2)
+	err := Wait(errc)
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "task2 panic", panicErr.Value)
+	assert.Equal(t, 1, panicErr.Task.Index)
+	assert.Contains(t, string(panicErr.Stack), "runner_test.go")
+}
+
+func Test_Runner_RunLimited_Panic(t *testing.T) {
+	// arrange
+	var count int32
+	task := func(ctx context.Context) error {
+		if atomic.AddInt32(&count, 1) == 3 {
+			panic(errors.New("task panic"))
+		}
+		return nil
+	}
+
+	// act
+	errc := NewRunner().RunLimited(context.Background(), 1, 5, task)
+	err := Wait(errc)
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, 0, panicErr.Task.Index)
+	assert.Equal(t, 2, panicErr.Task.Iteration)
+	assert.EqualError(t, errors.Unwrap(err), "task panic")
+}
+
+func Test_Runner_RunForever_Panic(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	task := func(ctx context.Context) error {
+		panic("task panic")
+	}
+
+	// act
+	errc := RunForeverContext(ctx, 2, task)
+	err := Wait(errc)
+	cancel()
+
+	// assert
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+}
+
+func Test_Runner_WithPanicRecovery_Disabled_Panics(t *testing.T) {
+	// arrange
+	opts := newOptions([]Option{WithPanicRecovery(false)})
+
+	task := func(ctx context.Context) error {
+		panic("task panic")
+	}
+
+	// act
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		opts.execute(context.Background(), TaskInfo{}, 0, task)
+	}()
+
+	// assert
+	assert.Equal(t, "task panic", recovered)
+}
@@ -0,0 +1,232 @@
+package async
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// semaphore limits access to a weighted capacity that can be resized while in use. Waiters with a higher priority are granted capacity first, and waiters with the same priority are granted capacity in the order they arrived.
+type semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	aging   time.Duration
+	waiters list.List
+	idle    chan struct{}
+	closed  bool
+}
+
+// waiter is a caller blocked in Acquire.
+type waiter struct {
+	n        int64
+	priority int
+	arrived  time.Time
+	ready    chan struct{}
+	err      error
+}
+
+// newSemaphore creates a new semaphore with the given capacity.
+func newSemaphore(n int64) *semaphore {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &semaphore{
+		size: n,
+		idle: idle,
+	}
+}
+
+// Acquire will block until n is available or the context is cancelled. Returns ErrWeightExceedsMax if n is larger than the capacity of the semaphore, or ErrPoolClosed if the semaphore is closed.
+func (s *semaphore) Acquire(ctx context.Context, n int64) error {
+	return s.AcquirePriority(ctx, n, 0)
+}
+
+// AcquirePriority will block until n is available or the context is cancelled, going ahead of waiters with a lower priority.
+func (s *semaphore) AcquirePriority(ctx context.Context, n int64, priority int) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrPoolClosed
+	}
+
+	if n > s.size {
+		s.mu.Unlock()
+		return ErrWeightExceedsMax
+	}
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.update()
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := &waiter{
+		n:        n,
+		priority: priority,
+		arrived:  time.Now(),
+		ready:    make(chan struct{}),
+	}
+	elem := s.waiters.PushBack(w)
+	s.update()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return w.err
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		if w.err != nil {
+			return w.err
+		}
+
+		// acquired after being cancelled; give it back
+		s.cur -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+	s.update()
+
+	return ctx.Err()
+}
+
+// TryAcquire acquires n only if it is available immediately and no one is waiting. Returns ErrPoolSaturated if it is not available.
+func (s *semaphore) TryAcquire(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.closed:
+		return ErrPoolClosed
+	case n > s.size:
+		return ErrWeightExceedsMax
+	case s.size-s.cur < n || s.waiters.Len() > 0:
+		return ErrPoolSaturated
+	}
+
+	s.cur += n
+	s.update()
+	return nil
+}
+
+// Release returns n to the semaphore.
+func (s *semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore released more than held")
+	}
+	s.update()
+}
+
+// Resize changes the capacity of the semaphore. Shrinking below the amount currently held does not affect holders; new callers wait until enough has been released.
+func (s *semaphore) Resize(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size = n
+	s.update()
+}
+
+// Close stops the semaphore from granting any more capacity. Waiters and later callers of Acquire fail with ErrPoolClosed, while holders may still release what they hold.
+func (s *semaphore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for e := s.waiters.Front(); e != nil; e = e.Next() {
+		w := e.Value.(*waiter)
+		w.err = ErrPoolClosed
+		close(w.ready)
+	}
+	s.waiters.Init()
+}
+
+// Closed reports whether the semaphore has been closed.
+func (s *semaphore) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// Wait will block until nothing is held or the context is cancelled.
+func (s *semaphore) Wait(ctx context.Context) error {
+	s.mu.Lock()
+	idle := s.idle
+	s.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// update grants capacity to waiters that fit and tracks whether the semaphore is idle. Must be called with the lock held.
+func (s *semaphore) update() {
+	now := time.Now()
+	for s.waiters.Len() > 0 {
+		next := s.next(now)
+		w := next.Value.(*waiter)
+		if w.n > s.size {
+			// the semaphore shrank below what the waiter needs
+			w.err = ErrWeightExceedsMax
+			s.waiters.Remove(next)
+			close(w.ready)
+			continue
+		}
+
+		if s.size-s.cur < w.n {
+			break
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+
+	select {
+	case <-s.idle:
+		if s.cur > 0 {
+			s.idle = make(chan struct{})
+		}
+	default:
+		if s.cur == 0 {
+			close(s.idle)
+		}
+	}
+}
+
+// next returns the waiter with the highest priority, raised by how long it has waited if aging is set. Must be called with the lock held.
+func (s *semaphore) next(now time.Time) *list.Element {
+	best := s.waiters.Front()
+	bestPriority := s.priority(best.Value.(*waiter), now)
+	for e := best.Next(); e != nil; e = e.Next() {
+		if p := s.priority(e.Value.(*waiter), now); p > bestPriority {
+			best, bestPriority = e, p
+		}
+	}
+
+	return best
+}
+
+// priority returns the priority of a waiter after aging.
+func (s *semaphore) priority(w *waiter, now time.Time) int {
+	if s.aging <= 0 {
+		return w.priority
+	}
+
+	return w.priority + int(now.Sub(w.arrived)/s.aging)
+}
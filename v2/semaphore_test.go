@@ -0,0 +1,193 @@
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_semaphore_Acquire_Success(t *testing.T) {
+	// arrange
+	sem := newSemaphore(2)
+
+	// act
+	err := sem.Acquire(context.Background(), 2)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), sem.cur)
+}
+
+func Test_semaphore_Acquire_Cancel(t *testing.T) {
+	// arrange
+	sem := newSemaphore(1)
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// act
+	err := sem.Acquire(ctx, 1)
+
+	// assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 0, sem.waiters.Len())
+	assert.Equal(t, int64(1), sem.cur)
+}
+
+func Test_semaphore_Release_GrantsInOrder(t *testing.T) {
+	// arrange
+	sem := newSemaphore(2)
+	assert.NoError(t, sem.Acquire(context.Background(), 2))
+
+	first := make(chan bool)
+	go func() {
+		assert.NoError(t, sem.Acquire(context.Background(), 2))
+		close(first)
+	}()
+	assertEventually(t, func() bool { return waiters(sem) == 1 })
+
+	second := make(chan bool)
+	go func() {
+		assert.NoError(t, sem.Acquire(context.Background(), 1))
+		close(second)
+	}()
+	assertEventually(t, func() bool { return waiters(sem) == 2 })
+
+	// act
+	sem.Release(1)
+
+	// assert
+	select {
+	case <-second:
+		assert.Fail(t, "waiter was granted out of order")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	sem.Release(1)
+	<-first
+	sem.Release(2)
+	<-second
+}
+
+func Test_semaphore_Resize_Grow(t *testing.T) {
+	// arrange
+	sem := newSemaphore(1)
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+
+	acquired := make(chan bool)
+	go func() {
+		assert.NoError(t, sem.Acquire(context.Background(), 1))
+		close(acquired)
+	}()
+
+	// act
+	sem.Resize(2)
+
+	// assert
+	<-acquired
+	assert.Equal(t, int64(2), sem.cur)
+}
+
+func Test_semaphore_Resize_Shrink(t *testing.T) {
+	// arrange
+	sem := newSemaphore(3)
+	assert.NoError(t, sem.Acquire(context.Background(), 3))
+
+	// act
+	sem.Resize(1)
+	sem.Release(1)
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	assert.Error(t, sem.Acquire(ctx, 1))
+
+	sem.Release(2)
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+}
+
+func Test_semaphore_Wait_Success(t *testing.T) {
+	// arrange
+	sem := newSemaphore(2)
+	assert.NoError(t, sem.Acquire(context.Background(), 2))
+
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		sem.Release(1)
+		time.Sleep(time.Millisecond * 50)
+		sem.Release(1)
+	}()
+
+	// act
+	start := time.Now()
+	err := sem.Wait(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Millisecond*100))
+}
+
+// waiters returns the number of callers blocked in Acquire.
+func waiters(sem *semaphore) int {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	return sem.waiters.Len()
+}
+
+func Test_semaphore_AcquirePriority_HigherFirst(t *testing.T) {
+	// arrange
+	sem := newSemaphore(1)
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+
+	order := make(chan int, 3)
+	for i, priority := range []int{0, 5, 1} {
+		go func(priority int) {
+			assert.NoError(t, sem.AcquirePriority(context.Background(), 1, priority))
+			order <- priority
+			sem.Release(1)
+		}(priority)
+		assertEventually(t, func() bool { return waiters(sem) == i+1 })
+	}
+
+	// act
+	sem.Release(1)
+
+	// assert
+	assert.Equal(t, 5, <-order)
+	assert.Equal(t, 1, <-order)
+	assert.Equal(t, 0, <-order)
+}
+
+func Test_semaphore_AcquirePriority_Aging(t *testing.T) {
+	// arrange
+	sem := newSemaphore(1)
+	sem.aging = time.Millisecond * 10
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+
+	order := make(chan int, 2)
+	go func() {
+		assert.NoError(t, sem.AcquirePriority(context.Background(), 1, 0))
+		order <- 0
+		sem.Release(1)
+	}()
+	assertEventually(t, func() bool { return waiters(sem) == 1 })
+
+	// the low priority waiter ages past the high priority waiter
+	time.Sleep(time.Millisecond * 100)
+	go func() {
+		assert.NoError(t, sem.AcquirePriority(context.Background(), 1, 3))
+		order <- 3
+		sem.Release(1)
+	}()
+	assertEventually(t, func() bool { return waiters(sem) == 2 })
+
+	// act
+	sem.Release(1)
+
+	// assert
+	assert.Equal(t, 0, <-order)
+	assert.Equal(t, 3, <-order)
+}
@@ -0,0 +1,153 @@
+package async
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// defaultBounds are the upper bounds of the buckets used by every histogram.
+var defaultBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Histogram is a snapshot of observed durations counted in buckets.
+type Histogram struct {
+	// Bounds are the upper bounds of each bucket in increasing order.
+	Bounds []time.Duration
+
+	// Counts are the number of observations in each bucket. The last count is for observations larger than every bound, so there is one more count than bounds.
+	Counts []uint64
+
+	// Sum is the total of every observation.
+	Sum time.Duration
+
+	// Count is the number of observations.
+	Count uint64
+}
+
+// PoolStats is a snapshot of the activity of a TaskPool.
+type PoolStats struct {
+	// InFlight is the number of tasks running.
+	InFlight int64
+
+	// Waiting is the number of callers waiting for capacity.
+	Waiting int64
+
+	// Leaked is the number of tasks abandoned after timing out that are still running.
+	Leaked int64
+
+	// Started is the number of tasks that have been started.
+	Started uint64
+
+	// Succeeded is the number of tasks that returned without an error.
+	Succeeded uint64
+
+	// Failed is the number of tasks that returned an error.
+	Failed uint64
+
+	// Panicked is the number of tasks that panicked.
+	Panicked uint64
+
+	// Duration counts how long tasks took to run.
+	Duration Histogram
+
+	// AcquireWait counts how long callers waited for capacity.
+	AcquireWait Histogram
+}
+
+// RunnerStats is a snapshot of the activity of a Runner.
+type RunnerStats struct {
+	// InFlight is the number of tasks running.
+	InFlight int64
+
+	// Leaked is the number of tasks abandoned after timing out that are still running.
+	Leaked int64
+
+	// Started is the number of tasks that have been started.
+	Started uint64
+
+	// Succeeded is the number of tasks that returned without an error.
+	Succeeded uint64
+
+	// Failed is the number of tasks that returned an error.
+	Failed uint64
+
+	// Panicked is the number of tasks that panicked.
+	Panicked uint64
+
+	// Duration counts how long tasks took to run.
+	Duration Histogram
+}
+
+// stats tracks the activity of a runner or pool using atomic counters.
+type stats struct {
+	inFlight    atomic.Int64
+	waiting     atomic.Int64
+	started     atomic.Uint64
+	succeeded   atomic.Uint64
+	failed      atomic.Uint64
+	panicked    atomic.Uint64
+	duration    histogram
+	acquireWait histogram
+}
+
+// finish records the result of a task that started at start.
+func (s *stats) finish(start time.Time, err error) {
+	s.duration.observe(time.Since(start))
+	s.inFlight.Add(-1)
+
+	switch err.(type) {
+	case nil:
+		s.succeeded.Add(1)
+	case *PanicError:
+		s.panicked.Add(1)
+	default:
+		s.failed.Add(1)
+	}
+}
+
+// histogram counts observed durations in buckets using atomic counters.
+type histogram struct {
+	counts [len(defaultBounds) + 1]atomic.Uint64
+	sum    atomic.Int64
+}
+
+// observe records a single duration.
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(defaultBounds) && d > defaultBounds[i] {
+		i++
+	}
+
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// snapshot returns the current counts. Count is the total of the counts that were loaded, so it always matches the buckets even while durations are being observed.
+func (h *histogram) snapshot() Histogram {
+	snap := Histogram{
+		Bounds: append([]time.Duration(nil), defaultBounds[:]...),
+		Counts: make([]uint64, len(defaultBounds)+1),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+
+	for i := range snap.Counts {
+		snap.Counts[i] = h.counts[i].Load()
+		snap.Count += snap.Counts[i]
+	}
+
+	return snap
+}
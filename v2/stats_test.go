@@ -0,0 +1,116 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_histogram_Observe_Success(t *testing.T) {
+	// arrange
+	var h histogram
+
+	// act
+	h.observe(time.Microsecond * 50)
+	h.observe(time.Millisecond)
+	h.observe(time.Millisecond * 3)
+	h.observe(time.Minute)
+
+	// assert
+	snap := h.snapshot()
+	assert.Equal(t, uint64(4), snap.Count)
+	assert.Equal(t, time.Microsecond*50+time.Millisecond*4+time.Minute, snap.Sum)
+	assert.Len(t, snap.Counts, len(snap.Bounds)+1)
+	assert.Equal(t, uint64(1), snap.Counts[0])
+	assert.Equal(t, uint64(1), snap.Counts[2])
+	assert.Equal(t, uint64(1), snap.Counts[3])
+	assert.Equal(t, uint64(1), snap.Counts[len(snap.Counts)-1])
+}
+
+func Test_histogram_Snapshot_Concurrent(t *testing.T) {
+	// arrange
+	var h histogram
+	stop := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.observe(time.Duration(i) * time.Millisecond)
+				}
+			}
+		}(i)
+	}
+
+	// act
+	mismatched := 0
+	for i := 0; i < 100000; i++ {
+		snap := h.snapshot()
+		var total uint64
+		for _, c := range snap.Counts {
+			total += c
+		}
+		if total != snap.Count {
+			mismatched++
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, 0, mismatched)
+}
+
+func Test_TaskPool_Stats_Success(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	// act
+	<-pool.Run(context.Background(), func() error { return nil })
+	<-pool.Run(context.Background(), func() error { return errors.New("task error") })
+	<-pool.Run(context.Background(), func() error { panic("task panic") })
+
+	// assert
+	stats := pool.Stats()
+	assert.Equal(t, uint64(3), stats.Started)
+	assert.Equal(t, uint64(1), stats.Succeeded)
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Equal(t, uint64(1), stats.Panicked)
+	assert.Equal(t, uint64(3), stats.Duration.Count)
+	assert.Equal(t, uint64(3), stats.AcquireWait.Count)
+	assert.NoError(t, pool.Wait())
+	assert.Equal(t, int64(0), pool.Stats().InFlight)
+}
+
+func Test_TaskPool_Stats_InFlightAndWaiting(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1)
+
+	release := make(chan bool)
+	task := func() error {
+		<-release
+		return nil
+	}
+	pool.Run(context.Background(), task)
+	go pool.Run(context.Background(), task)
+
+	// act
+	assertEventually(t, func() bool { return pool.Stats().Waiting == 1 })
+	stats := pool.Stats()
+
+	// assert
+	assert.Equal(t, int64(1), stats.InFlight)
+	assert.Equal(t, int64(1), stats.Waiting)
+
+	close(release)
+	assertEventually(t, func() bool { return pool.Stats().Succeeded == 2 })
+}
@@ -0,0 +1,136 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// OrderedStream reads items from in, processes them with fn on the given number of workers and sends the results on the returned channel in the same order the items were read. At most window items are processed or held waiting for an earlier item at once, so a slow item holds up reading further input rather than growing memory. The error channel receives the first error from fn, or the context's error if it is cancelled, and is closed along with the results channel once the stream has stopped. The stream stops after the first error.
+func OrderedStream[In any, Out any](ctx context.Context, in <-chan In, workers int, window int, fn func(ctx context.Context, item In) (Out, error)) (<-chan Out, <-chan error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if window < workers {
+		window = workers
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+
+	type job struct {
+		seq  int
+		item In
+	}
+
+	type result struct {
+		seq int
+		out Out
+		err error
+	}
+
+	out := make(chan Out)
+	errc := make(chan error, 1)
+	jobs := make(chan job)
+	results := make(chan result, window)
+	slots := make(chan struct{}, window)
+
+	// read input while there is room in the window
+	go func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			var item In
+			var ok bool
+			select {
+			case item, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case jobs <- job{seq: seq, item: item}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// process items
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				var r Out
+				err := defaultRunner.opts.call(ctx, TaskInfo{TaskName: TaskName(ctx), Index: j.seq}, func(ctx context.Context) error {
+					var err error
+					r, err = fn(ctx, j.item)
+					return err
+				})
+
+				// never blocks since results holds as many items as the window
+				results <- result{seq: j.seq, out: r, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// emit results in order
+	go func() {
+		defer cancel()
+		defer close(errc)
+		defer close(out)
+
+		pending := make(map[int]result)
+		next := 0
+		var firstErr error
+
+		for r := range results {
+			if firstErr != nil {
+				// drain results from items still being processed
+				continue
+			}
+
+			if r.err != nil {
+				firstErr = r.err
+				cancel()
+				continue
+			}
+
+			pending[r.seq] = r
+			for p, ok := pending[next]; ok && firstErr == nil; p, ok = pending[next] {
+				select {
+				case out <- p.out:
+					delete(pending, next)
+					next++
+					<-slots
+				case <-ctx.Done():
+					firstErr = ctx.Err()
+				}
+			}
+		}
+
+		if firstErr == nil {
+			firstErr = parent.Err()
+		}
+
+		if firstErr != nil {
+			errc <- firstErr
+		}
+	}()
+
+	return out, errc
+}
@@ -0,0 +1,129 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// feed returns a channel that sends the given items and then closes.
+func feed(items ...int) <-chan int {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for _, item := range items {
+			in <- item
+		}
+	}()
+	return in
+}
+
+func Test_OrderedStream_Success(t *testing.T) {
+	// arrange
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	// act
+	out, errc := OrderedStream(context.Background(), feed(items...), 4, 8, func(ctx context.Context, item int) (int, error) {
+		// later items finish first
+		time.Sleep(time.Duration(50-item) * time.Microsecond * 20)
+		return item * 2, nil
+	})
+
+	var results []int
+	for r := range out {
+		results = append(results, r)
+	}
+
+	// assert
+	assert.NoError(t, Wait(errc))
+	assert.Len(t, results, 50)
+	for i, r := range results {
+		assert.Equal(t, i*2, r)
+	}
+}
+
+func Test_OrderedStream_Window(t *testing.T) {
+	// arrange
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+	}()
+
+	release := make(chan bool)
+	var started int32
+
+	// act
+	out, errc := OrderedStream(context.Background(), in, 4, 6, func(ctx context.Context, item int) (int, error) {
+		atomic.AddInt32(&started, 1)
+		if item == 0 {
+			<-release
+		}
+		return item, nil
+	})
+
+	// assert
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&started))
+
+	close(release)
+	count := 0
+	for range out {
+		count++
+	}
+	assert.Equal(t, 100, count)
+	assert.NoError(t, Wait(errc))
+}
+
+func Test_OrderedStream_Error(t *testing.T) {
+	// arrange
+	errItem := errors.New("item error")
+
+	// act
+	out, errc := OrderedStream(context.Background(), feed(0, 1, 2, 3, 4, 5, 6, 7), 2, 4, func(ctx context.Context, item int) (int, error) {
+		if item == 3 {
+			return 0, errItem
+		}
+		return item, nil
+	})
+
+	var results []int
+	for r := range out {
+		results = append(results, r)
+	}
+
+	// assert
+	assert.Equal(t, errItem, Wait(errc))
+	for i, r := range results {
+		assert.Equal(t, i, r)
+	}
+	assert.Less(t, len(results), 4)
+}
+
+func Test_OrderedStream_Cancel(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+
+	// act
+	out, errc := OrderedStream(ctx, in, 2, 2, func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	})
+	in <- 1
+	assert.Equal(t, 1, <-out)
+	cancel()
+
+	// assert
+	_, ok := <-out
+	assert.False(t, ok)
+	assert.Equal(t, context.Canceled, Wait(errc))
+}
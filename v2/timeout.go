@@ -0,0 +1,71 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTaskTimeout is returned when a task runs longer than the timeout set with WithTimeout.
+var ErrTaskTimeout = errors.New("task timed out")
+
+// TimeoutPolicy determines what happens to a task that is still running after its timeout expires.
+type TimeoutPolicy int
+
+const (
+	// TimeoutWait waits for the task to return after its context is cancelled, so the task keeps holding its goroutine and any pool capacity until it returns.
+	TimeoutWait TimeoutPolicy = iota
+
+	// TimeoutAbandon returns as soon as the timeout expires and leaves the task running in the background. Abandoned tasks that are still running are counted as leaked.
+	TimeoutAbandon
+)
+
+// executeTimeout runs a single task with a context that is cancelled once the timeout expires.
+func (o *options) executeTimeout(ctx context.Context, info TaskInfo, task TaskContext) error {
+	tctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	if o.timeoutPolicy == TimeoutWait {
+		return timeoutErr(ctx, tctx, o.call(tctx, info, task))
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- timeoutErr(ctx, tctx, o.call(tctx, info, task))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-tctx.Done():
+	}
+
+	// abandon the task and track it until it returns
+	o.leaked.Add(1)
+	go func() {
+		<-done
+		o.leaked.Add(-1)
+	}()
+
+	if timedOut(ctx, tctx) {
+		return ErrTaskTimeout
+	}
+	return ctx.Err()
+}
+
+// timeoutErr returns the error of a task that has returned, reporting ErrTaskTimeout if the task ran past its timeout even if it returned nil. A task's own error is kept so it matches both.
+func timeoutErr(ctx context.Context, tctx context.Context, err error) error {
+	if !timedOut(ctx, tctx) {
+		return err
+	}
+
+	if err == nil {
+		return ErrTaskTimeout
+	}
+	return fmt.Errorf("%w: %w", ErrTaskTimeout, err)
+}
+
+// timedOut reports whether tctx expired because of its own timeout rather than cancellation of ctx.
+func timedOut(ctx context.Context, tctx context.Context) bool {
+	return tctx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+}
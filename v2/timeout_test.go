@@ -0,0 +1,152 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func Test_WithTimeout_Wait(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Millisecond*50, TimeoutWait))
+
+	task := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	// act
+	errc := runner.Run(context.Background(), task)
+	err := Wait(errc)
+
+	// assert
+	assert.True(t, errors.Is(err, ErrTaskTimeout))
+	assert.Equal(t, 0, runner.Leaked())
+}
+
+func Test_WithTimeout_Wait_IgnoresContext(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Millisecond*20, TimeoutWait))
+
+	task := func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 60)
+		return nil
+	}
+
+	// act
+	errc := runner.Run(context.Background(), task)
+	err := Wait(errc)
+
+	// assert
+	assert.Equal(t, ErrTaskTimeout, err)
+}
+
+func Test_WithTimeout_Wait_KeepsTaskError(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Millisecond*20, TimeoutWait))
+
+	errTask := errors.New("task error")
+	task := func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 60)
+		return errTask
+	}
+
+	// act
+	errc := runner.Run(context.Background(), task)
+	err := Wait(errc)
+
+	// assert
+	assert.True(t, errors.Is(err, ErrTaskTimeout))
+	assert.True(t, errors.Is(err, errTask))
+	assert.EqualError(t, err, "task timed out: task error")
+}
+
+func Test_WithTimeout_Success(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Millisecond*200, TimeoutAbandon))
+
+	task := func(ctx context.Context) error {
+		return nil
+	}
+
+	// act
+	errc := runner.RunLimited(context.Background(), 2, 5, task)
+	err := Wait(errc)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func Test_WithTimeout_Abandon(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Millisecond*50, TimeoutAbandon))
+
+	release := make(chan bool)
+	task := func(ctx context.Context) error {
+		// ignore the context
+		<-release
+		return nil
+	}
+
+	// act
+	start := time.Now()
+	errc := runner.Run(context.Background(), task)
+	err := Wait(errc)
+	elapsed := time.Since(start)
+
+	// assert
+	assert.True(t, errors.Is(err, ErrTaskTimeout))
+	assert.Less(t, int64(elapsed), int64(time.Second))
+	assert.Equal(t, 1, runner.Leaked())
+
+	close(release)
+	assertEventually(t, func() bool { return runner.Leaked() == 0 })
+}
+
+func Test_WithTimeout_Cancel(t *testing.T) {
+	// arrange
+	runner := NewRunner(WithTimeout(time.Second*5, TimeoutWait))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	task := func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	// act
+	errc := runner.Run(ctx, task)
+	err := Wait(errc)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, ErrTaskTimeout))
+}
+
+func Test_TaskPool_WithTimeout_Abandon(t *testing.T) {
+	// arrange
+	pool := NewTaskPool(1, WithTimeout(time.Millisecond*50, TimeoutAbandon))
+
+	release := make(chan bool)
+	task := func() error {
+		<-release
+		return nil
+	}
+
+	// act
+	err := <-pool.Run(context.Background(), task)
+
+	// assert
+	assert.True(t, errors.Is(err, ErrTaskTimeout))
+	assert.Equal(t, 1, pool.Leaked())
+
+	// the slot is available again even though the task is still running
+	err = <-pool.Run(context.Background(), func() error { return nil })
+	assert.NoError(t, err)
+
+	close(release)
+	assertEventually(t, func() bool { return pool.Leaked() == 0 })
+}
@@ -0,0 +1,59 @@
+package async
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer is notified before and after every task executed by a Runner or TaskPool, so task executions can be tied back to traces. A tracer must be safe for concurrent use.
+type Tracer interface {
+	// TaskStart is called before a task is executed. The returned context is given to the task and to TaskEnd, so a span can be attached to it.
+	TaskStart(ctx context.Context, event TaskEvent) context.Context
+
+	// TaskEnd is called after a task returns with the error it returned, if any.
+	TaskEnd(ctx context.Context, event TaskEvent, err error)
+}
+
+// TaskEvent describes a single execution of a task.
+type TaskEvent struct {
+	// Task identifies the task. Task.Iteration is the attempt when a task is executed repeatedly by RunForever or RunLimited.
+	Task TaskInfo
+
+	// Wait is how long the task waited to be executed, either for capacity in a TaskPool or for the rate limiter of a Runner.
+	Wait time.Duration
+}
+
+// TracerFuncs adapts a pair of functions to a Tracer. Either function may be nil. This makes it simple to bridge to a tracing library, such as OpenTelemetry, without the async package depending on it:
+//
+//	async.TracerFuncs{
+//		Start: func(ctx context.Context, e async.TaskEvent) context.Context {
+//			ctx, _ = tracer.Start(ctx, e.Task.String())
+//			return ctx
+//		},
+//		End: func(ctx context.Context, e async.TaskEvent, err error) {
+//			span := trace.SpanFromContext(ctx)
+//			if err != nil {
+//				span.RecordError(err)
+//			}
+//			span.End()
+//		},
+//	}
+type TracerFuncs struct {
+	Start func(ctx context.Context, event TaskEvent) context.Context
+	End   func(ctx context.Context, event TaskEvent, err error)
+}
+
+// TaskStart calls Start if it is set.
+func (t TracerFuncs) TaskStart(ctx context.Context, event TaskEvent) context.Context {
+	if t.Start == nil {
+		return ctx
+	}
+	return t.Start(ctx, event)
+}
+
+// TaskEnd calls End if it is set.
+func (t TracerFuncs) TaskEnd(ctx context.Context, event TaskEvent, err error) {
+	if t.End != nil {
+		t.End(ctx, event, err)
+	}
+}
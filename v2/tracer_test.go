@@ -0,0 +1,120 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// spanKey is the context key used by recordingTracer.
+type spanKey struct{}
+
+// recordingTracer records the events it is given.
+type recordingTracer struct {
+	mu     sync.Mutex
+	starts []TaskEvent
+	ends   []TaskEvent
+	errs   []error
+}
+
+func (t *recordingTracer) TaskStart(ctx context.Context, event TaskEvent) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.starts = append(t.starts, event)
+	return context.WithValue(ctx, spanKey{}, event.Task.Index)
+}
+
+func (t *recordingTracer) TaskEnd(ctx context.Context, event TaskEvent, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ends = append(t.ends, event)
+	t.errs = append(t.errs, err)
+}
+
+func Test_Runner_WithTracer_Success(t *testing.T) {
+	// arrange
+	tracer := &recordingTracer{}
+	runner := NewRunner(WithName("loader"), WithTracer(tracer), WithTaskErrors(true))
+
+	errTask := errors.New("task error")
+	task := func(ctx context.Context) error {
+		assert.Equal(t, 0, ctx.Value(spanKey{}))
+		return errTask
+	}
+
+	// act
+	errc := runner.RunLimited(context.Background(), 1, 3, task)
+	err := WaitAll(errc)
+
+	// assert
+	assert.Error(t, err)
+	assert.Len(t, tracer.starts, 3)
+	assert.Len(t, tracer.ends, 3)
+	for i, event := range tracer.ends {
+		assert.Equal(t, "loader", event.Task.Name)
+		assert.Equal(t, 0, event.Task.Index)
+		assert.Equal(t, i, event.Task.Iteration)
+		assert.Equal(t, errTask, tracer.errs[i])
+	}
+	assert.Contains(t, err.Error(), "loader task 0: task error")
+}
+
+func Test_TaskPool_WithTracer_TaskName(t *testing.T) {
+	// arrange
+	tracer := &recordingTracer{}
+	pool := NewTaskPool(1, WithName("images"), WithTracer(tracer))
+
+	// act
+	err := <-pool.RunContext(WithTaskName(context.Background(), "resize"), func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+
+	// assert
+	assert.Len(t, tracer.starts, 1)
+	assert.Equal(t, "images", tracer.starts[0].Task.Name)
+	assert.Equal(t, "resize", tracer.starts[0].Task.TaskName)
+	assert.Equal(t, err, tracer.errs[0])
+}
+
+func Test_TaskPool_WithTracer_Wait(t *testing.T) {
+	// arrange
+	tracer := &recordingTracer{}
+	pool := NewTaskPool(1, WithName("images"), WithTracer(tracer))
+
+	pool.Run(context.Background(), func() error {
+		time.Sleep(time.Millisecond * 50)
+		return nil
+	})
+
+	// act
+	err := <-pool.Run(context.Background(), func() error {
+		return nil
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, pool.Wait())
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	assert.Len(t, tracer.ends, 2)
+	assert.Equal(t, "images", tracer.ends[1].Task.Name)
+	assert.GreaterOrEqual(t, int64(tracer.ends[1].Wait), int64(time.Millisecond*40))
+}
+
+func Test_TracerFuncs_Nil(t *testing.T) {
+	// arrange
+	var tracer TracerFuncs
+	ctx := context.Background()
+
+	// act
+	result := tracer.TaskStart(ctx, TaskEvent{})
+	tracer.TaskEnd(ctx, TaskEvent{}, nil)
+
+	// assert
+	assert.Equal(t, ctx, result)
+}